@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func init() {
+	onActivity(achievementActivity)
+}
+
+// achievementActivity advances every unawarded achievement an activity counts towards
+func achievementActivity(a Activity) {
+	awarded := redisClient.HGetAll(AchievedKey(a.UserID)).Val()
+	for _, ach := range Achieve.Achievements {
+		if _, ok := awarded[ach.ID]; ok {
+			continue
+		}
+		progress, changed, err := advanceRule(AchieveProgKey(a.UserID), ach.ID, ach.Rule, a)
+		if err != nil {
+			logError("Unable to advance achievement progress", err)
+			continue
+		}
+		if !changed || ach.Rule.goal() < 1 || progress < ach.Rule.goal() {
+			continue
+		}
+		DBAwardAchievement(a.UserID, ach)
+	}
+}
+
+// DBAwardAchievement awards an achievement to a user once and pays out its reward
+func DBAwardAchievement(userID string, ach Achievement) {
+	ok, err := redisClient.HSetNX(AchievedKey(userID), ach.ID, CurrentTime.Unix()).Result()
+	if err != nil {
+		logError("Unable to award achievement", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	if err := DBGiveReward(userID, ach.Reward); err != nil {
+		logError("Unable to give achievement reward", err)
+	}
+	log.WithFields(log.Fields{
+		"user":        userID,
+		"achievement": ach.ID,
+	}).Debug("achievement-awarded")
+}
+
+// DBGetAchievements returns a users progress towards every achievement
+func DBGetAchievements(userID string) []AchievementStatus {
+	statuses := []AchievementStatus{}
+	awarded := redisClient.HGetAll(AchievedKey(userID)).Val()
+	progress := redisClient.HGetAll(AchieveProgKey(userID)).Val()
+	for _, ach := range Achieve.Achievements {
+		status := AchievementStatus{Achievement: ach}
+		status.Progress, _ = strconv.Atoi(progress[ach.ID])
+		if at, ok := awarded[ach.ID]; ok {
+			status.Awarded = true
+			if u, err := strconv.ParseInt(at, 10, 64); err == nil {
+				t := time.Unix(u, 0).UTC()
+				status.AwardedAt = &t
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// activityListeners are called for every activity emitted by the cast, sell, purchase and craft flows
+var activityListeners []func(Activity)
+
+// onActivity registers a listener for player activities
+func onActivity(l func(Activity)) {
+	activityListeners = append(activityListeners, l)
+}
+
+// activities queues emitted activities so listeners see them in the order they happened
+var activities = make(chan Activity, 1024)
+
+// emitActivity stamps an activity with when it happened and queues it to be passed to every listener in the background,
+// the activity is dropped rather than holding up the request when the queue is full
+func emitActivity(a Activity) {
+	a.At = time.Now().UTC()
+	select {
+	case activities <- a:
+	default:
+		log.WithFields(log.Fields{
+			"type": a.Type,
+			"user": a.UserID,
+		}).Error("Activity queue is full, dropping activity")
+	}
+}
+
+// dispatchActivities passes queued activities to every listener one at a time
+func dispatchActivities() {
+	for a := range activities {
+		for _, l := range activityListeners {
+			dispatch(l, a)
+		}
+	}
+}
+
+// dispatch passes an activity to a listener, a listener that panics is logged so the rest keep being called
+func dispatch(l func(Activity), a Activity) {
+	defer func() {
+		if r := recover(); r != nil {
+			logError("Activity listener panicked", fmt.Errorf("%v", r))
+		}
+	}()
+	l(a)
+}
+
+// castOutcomes are the activities that can result from a single cast
+var castOutcomes = map[string]bool{
+	"fish":    true,
+	"garbage": true,
+	"fail":    true,
+}
+
+// matches reports whether an activity counts towards a rule
+func (r Rule) matches(a Activity) bool {
+	if r.Activity != a.Type {
+		return false
+	}
+	if r.Location != "" && r.Location != a.Location {
+		return false
+	}
+	if r.Tier != 0 && r.Tier != a.Fish.Tier {
+		return false
+	}
+	if r.Species != "" && r.Species != a.Fish.Name {
+		return false
+	}
+	if r.Item != "" && r.Item != a.Item {
+		return false
+	}
+	return true
+}
+
+// increment returns how much an activity matching a rule adds to its progress
+func (r Rule) increment(a Activity) int {
+	if r.Kind == "sum" {
+		return a.Amount
+	}
+	return 1
+}
+
+// goal returns the progress needed to complete a rule
+func (r Rule) goal() int {
+	if r.Kind == "collection" {
+		return len(collectionSpecies(r))
+	}
+	return r.Target
+}
+
+// collectionSpecies returns the names of every fish a collection rule requires
+func collectionSpecies(r Rule) map[string]bool {
+	species := map[string]bool{}
+	for _, loc := range fishLocations {
+		if r.Location != "" && r.Location != loc {
+			continue
+		}
+		for i, t := range locationTiers(loc) {
+			if r.Tier != 0 && r.Tier != i+1 {
+				continue
+			}
+			for _, f := range t.Fish {
+				species[f.Name] = true
+			}
+		}
+	}
+	return species
+}
+
+// advanceRule applies an activity to the progress of a rule stored in a hash field,
+// it returns the new progress and whether the activity affected it
+func advanceRule(key, field string, r Rule, a Activity) (int, bool, error) {
+	switch r.Kind {
+	case "streak":
+		if r.matches(a) {
+			n, err := redisClient.HIncrBy(key, field, 1).Result()
+			return int(n), true, err
+		}
+		if castOutcomes[a.Type] && castOutcomes[r.Activity] {
+			return 0, true, redisClient.HSet(key, field, 0).Err()
+		}
+		return 0, false, nil
+
+	case "collection":
+		if a.Type != "fish" || (r.Location != "" && r.Location != a.Location) {
+			return 0, false, nil
+		}
+		entries := DBGetFishdex(a.UserID)
+		n := 0
+		for name := range collectionSpecies(r) {
+			if entries[name].Caught || name == a.Fish.Name {
+				n++
+			}
+		}
+		return n, true, redisClient.HSet(key, field, n).Err()
+
+	default:
+		if !r.matches(a) {
+			return 0, false, nil
+		}
+		n, err := redisClient.HIncrBy(key, field, int64(r.increment(a))).Result()
+		return int(n), true, err
+	}
+}
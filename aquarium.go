@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/go-redis/redis"
+)
+
+// rarityPoints is how much a fish of each tier adds to an aquariums rarity score
+var rarityPoints = map[int]int{1: 1, 2: 2, 3: 4, 4: 8, 5: 16}
+
+func (m AquariumMove) validate() error {
+	if len(m.Fish) == 0 {
+		return errors.New("No fish given")
+	}
+	seen := map[string]bool{}
+	for _, id := range m.Fish {
+		if seen[id] {
+			return fmt.Errorf("Fish %s is listed more than once", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// aquariumFish reads every fish in a users aquarium with the given client so it can be used inside transactions
+func aquariumFish(c redis.Cmdable, userID string) []InvFish {
+	fish := []InvFish{}
+	for _, data := range c.HVals(AquariumKey(userID)).Val() {
+		var f InvFish
+		if err := json.Unmarshal([]byte(data), &f); err != nil {
+			logError("Unable to unmarshal aquarium fish", err)
+			continue
+		}
+		fish = append(fish, f)
+	}
+	sort.Slice(fish, func(i, j int) bool {
+		a, _ := strconv.Atoi(fish[i].ID)
+		b, _ := strconv.Atoi(fish[j].ID)
+		return a < b
+	})
+	return fish
+}
+
+func aquariumValue(fish []InvFish) float64 {
+	v := 0.0
+	for _, f := range fish {
+		v += f.Price
+	}
+	return v
+}
+
+// DBGetAquarium returns the fish in a users aquarium with their total value and rarity score
+func DBGetAquarium(userID string) Aquarium {
+	fish := aquariumFish(redisClient, userID)
+	a := Aquarium{fish, DBGetAquariumCapacity(userID), aquariumValue(fish), 0}
+	for _, f := range fish {
+		a.Rarity += rarityPoints[f.Tier]
+	}
+	return a
+}
+
+// DBShowFish moves fish out of a users sellable inventory into their aquarium
+func DBShowFish(userID string, ids []string) error {
+	slots := DBGetAquariumCapacity(userID)
+	return watchTx(func(tx *redis.Tx) error {
+		fish, err := fishByID(tx, userID, ids)
+		if err != nil {
+			return err
+		}
+		shown := aquariumFish(tx, userID)
+		if len(shown)+len(fish) > slots {
+			return fmt.Errorf("Your aquarium only has room for %v fish", slots)
+		}
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			queueRemoveFish(pipe, userID, fish)
+			for _, f := range fish {
+				data, _ := json.Marshal(f)
+				pipe.HSet(AquariumKey(userID), f.ID, data)
+			}
+			pipe.ZAdd(AquariumValueKey, redis.Z{Score: aquariumValue(append(shown, fish...)), Member: userID})
+			return nil
+		})
+		return err
+	}, CatchesKey(userID), FishInvKey(userID), AquariumKey(userID))
+}
+
+// DBUnshowFish moves fish out of a users aquarium back into their inventory
+func DBUnshowFish(userID string, ids []string) error {
+	capacity := DBGetInvCapacity(userID)
+	return watchTx(func(tx *redis.Tx) error {
+		shown := aquariumFish(tx, userID)
+		byID := map[string]InvFish{}
+		for _, f := range shown {
+			byID[f.ID] = f
+		}
+		var fish []InvFish
+		for _, id := range ids {
+			f, ok := byID[id]
+			if !ok {
+				return fmt.Errorf("Fish %s is not in your aquarium", id)
+			}
+			fish = append(fish, f)
+			delete(byID, id)
+		}
+		if invSize(tx, userID)+len(fish) > capacity {
+			return errors.New("You do not have enough room in your inventory")
+		}
+		left := []InvFish{}
+		for _, f := range byID {
+			left = append(left, f)
+		}
+		_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+			for _, f := range fish {
+				pipe.HDel(AquariumKey(userID), f.ID)
+			}
+			queueAddFish(pipe, userID, fish)
+			pipe.ZAdd(AquariumValueKey, redis.Z{Score: aquariumValue(left), Member: userID})
+			return nil
+		})
+		return err
+	}, CatchesKey(userID), FishInvKey(userID), AquariumKey(userID))
+}
+
+// DBGetAquariumBoard returns the 10 most valuable aquariums among the members of a guild
+func DBGetAquariumBoard(guildID string) ([]AquariumRank, error) {
+	key := AquariumTopKey(guildID)
+	board := []AquariumRank{}
+	if err := guildBoard(key, guildID, AquariumValueKey); err != nil {
+		return board, err
+	}
+	z, err := redisClient.ZRevRangeWithScores(key, 0, 9).Result()
+	if err != nil {
+		return board, err
+	}
+	for i, e := range z {
+		user := e.Member.(string)
+		board = append(board, AquariumRank{int64(i + 1), user, DBGetTrackedUser(user), e.Score})
+	}
+	return board, nil
+}
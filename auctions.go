@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis"
+	log "github.com/sirupsen/logrus"
+)
+
+func (a Auction) validate() error {
+	if a.FishID == "" {
+		return errors.New("A fish to auction is required")
+	}
+	if a.Reserve < 1 {
+		return errors.New("Reserve price must be positive")
+	}
+	length := a.Ends.Sub(a.Created)
+	if length < AuctionMinLength || length > AuctionMaxLength {
+		return fmt.Errorf("Auctions must last between %v and %v", AuctionMinLength, AuctionMaxLength)
+	}
+	return nil
+}
+
+func getAuction(c redis.Cmdable, auctionID string) (Auction, error) {
+	var a Auction
+	data, err := c.Get(AuctionKey(auctionID)).Result()
+	if err == redis.Nil {
+		return a, fmt.Errorf("Auction %s does not exist", auctionID)
+	}
+	if err != nil {
+		return a, err
+	}
+	err = json.Unmarshal([]byte(data), &a)
+	return a, err
+}
+
+// trackAuction queues adding an auction to a users auction history without duplicating it
+func trackAuction(pipe redis.Pipeliner, userID, auctionID string) {
+	pipe.LRem(AuctionsKey(userID), 0, auctionID)
+	pipe.LPush(AuctionsKey(userID), auctionID)
+	pipe.LTrim(AuctionsKey(userID), 0, 49)
+}
+
+// DBCreateAuction takes a fish out of the sellers inventory and lists it on the auction house
+func DBCreateAuction(a Auction) (Auction, error) {
+	id, err := redisClient.Incr(AuctionIDKey).Result()
+	if err != nil {
+		return Auction{}, err
+	}
+	a.ID = strconv.FormatInt(id, 10)
+	a.Status = "open"
+	err = watchTx(func(tx *redis.Tx) error {
+		fish, err := fishByID(tx, a.Seller, []string{a.FishID})
+		if err != nil {
+			return err
+		}
+		a.Fish = fish[0]
+		data, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			queueRemoveFish(pipe, a.Seller, fish)
+			pipe.Set(AuctionKey(a.ID), data, 0)
+			pipe.ZAdd(AuctionEndKey, redis.Z{Score: float64(a.Ends.Unix()), Member: a.ID})
+			trackAuction(pipe, a.Seller, a.ID)
+			return nil
+		})
+		return err
+	}, CatchesKey(a.Seller), FishInvKey(a.Seller))
+	return a, err
+}
+
+// DBBidAuction places a bid on an open auction, the bid is taken from the bidders wallet
+// and the previous high bidder is refunded. Raising your own bid only takes the difference
+func DBBidAuction(userID, auctionID string, amount int) (Auction, error) {
+	var a Auction
+	err := watchTx(func(tx *redis.Tx) error {
+		var err error
+		a, err = getAuction(tx, auctionID)
+		if err != nil {
+			return err
+		}
+		if a.Status != "open" || !CurrentTime.Before(a.Ends) {
+			return errors.New("Auction has ended")
+		}
+		if a.Seller == userID {
+			return errors.New("You cannot bid on your own auction")
+		}
+		if amount < a.Reserve {
+			return fmt.Errorf("Bid must be at least the reserve price of %v yen", a.Reserve)
+		}
+		if amount <= a.HighBid {
+			return fmt.Errorf("Bid must be higher than the current bid of %v yen", a.HighBid)
+		}
+		cost := amount
+		if a.HighBidder == userID {
+			cost -= a.HighBid
+		}
+		if walletBalance(tx, userID) < cost {
+			return fmt.Errorf("%s does not have %v yen", DBGetTrackedUser(userID), cost)
+		}
+		prev, prevBid := a.HighBidder, a.HighBid
+		a.HighBidder, a.HighBid = userID, amount
+		data, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.IncrBy(WalletKey(userID), int64(-cost))
+			if prev != "" && prev != userID {
+				pipe.IncrBy(WalletKey(prev), int64(prevBid))
+			}
+			pipe.Set(AuctionKey(a.ID), data, 0)
+			trackAuction(pipe, userID, a.ID)
+			return nil
+		})
+		return err
+	}, AuctionKey(auctionID), WalletKey(userID))
+	return a, err
+}
+
+// settleAuction closes an expired auction, handing the fish to the high bidder and their escrowed bid to the seller,
+// unsold fish go back to the seller. Fish are delivered even if they overfill an inventory
+func settleAuction(auctionID string) (Auction, error) {
+	var a Auction
+	err := watchTx(func(tx *redis.Tx) error {
+		var err error
+		a, err = getAuction(tx, auctionID)
+		if err != nil {
+			return err
+		}
+		if a.Status != "open" {
+			tx.ZRem(AuctionEndKey, a.ID)
+			return fmt.Errorf("Auction is already %s", a.Status)
+		}
+		owner := a.Seller
+		a.Status = "unsold"
+		if a.HighBidder != "" {
+			owner = a.HighBidder
+			a.Status = "sold"
+		}
+		data, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			queueAddFish(pipe, owner, []InvFish{a.Fish})
+			if a.Status == "sold" {
+				pipe.IncrBy(WalletKey(a.Seller), int64(a.HighBid))
+			}
+			pipe.Set(AuctionKey(a.ID), data, 0)
+			pipe.ZRem(AuctionEndKey, a.ID)
+			return nil
+		})
+		return err
+	}, AuctionKey(auctionID))
+	return a, err
+}
+
+// settleAuctions settles every auction that has run out of time
+func settleAuctions() {
+	ids, err := redisClient.ZRangeByScore(AuctionEndKey, redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(CurrentTime.Unix(), 10),
+	}).Result()
+	if err != nil {
+		logError("Unable to retrieve expired auctions", err)
+		return
+	}
+	for _, id := range ids {
+		a, err := settleAuction(id)
+		if err != nil {
+			logError("Unable to settle auction", err)
+			continue
+		}
+		log.WithFields(log.Fields{
+			"auction": a.ID,
+			"seller":  a.Seller,
+			"winner":  a.HighBidder,
+			"bid":     a.HighBid,
+			"status":  a.Status,
+		}).Debug("auction-settled")
+	}
+}
+
+// DBGetAuctions returns every open auction, ending soonest first
+func DBGetAuctions() []Auction {
+	return getAuctions(redisClient.ZRange(AuctionEndKey, 0, -1).Val())
+}
+
+// DBGetUserAuctions returns the auctions a user has recently listed or bid on
+func DBGetUserAuctions(userID string) []Auction {
+	return getAuctions(redisClient.LRange(AuctionsKey(userID), 0, 24).Val())
+}
+
+func getAuctions(ids []string) []Auction {
+	auctions := []Auction{}
+	for _, id := range ids {
+		a, err := getAuction(redisClient, id)
+		if err != nil {
+			logError("Unable to retrieve auction", err)
+			continue
+		}
+		auctions = append(auctions, a)
+	}
+	return auctions
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// listTestAuction lists a fish of the seller on the auction house for an hour
+func listTestAuction(t *testing.T, seller string, f InvFish, reserve int) Auction {
+	t.Helper()
+	f = giveTestFish(t, seller, f)
+	a, err := DBCreateAuction(Auction{
+		Seller:  seller,
+		FishID:  f.ID,
+		Reserve: reserve,
+		Created: CurrentTime,
+		Ends:    CurrentTime.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("creating auction: %v", err)
+	}
+	return a
+}
+
+func TestSettleAuctionPaysSellerAndDeliversFish(t *testing.T) {
+	newTestRedis(t)
+	a := listTestAuction(t, "alice", InvFish{Name: "Marlin", Price: 300, Tier: 5}, 100)
+	if hasFish("alice", a.Fish.ID) {
+		t.Fatal("listed fish is still in the sellers inventory")
+	}
+	DBAddWallet("bob", 500)
+	DBAddWallet("carol", 500)
+
+	if _, err := DBBidAuction("bob", a.ID, 150); err != nil {
+		t.Fatalf("bob bidding: %v", err)
+	}
+	if _, err := DBBidAuction("carol", a.ID, 200); err != nil {
+		t.Fatalf("carol bidding: %v", err)
+	}
+	if _, err := DBBidAuction("bob", a.ID, 180); err == nil {
+		t.Error("a bid below the high bid was accepted")
+	}
+	if got := DBGetWallet("bob"); got != 500 {
+		t.Errorf("outbid wallet = %v, want the bid refunded to 500", got)
+	}
+	if got := DBGetWallet("carol"); got != 300 {
+		t.Errorf("high bidder wallet = %v, want 300 with the bid escrowed", got)
+	}
+
+	CurrentTime = a.Ends.Add(time.Minute)
+	settleAuctions()
+
+	a, err := getAuction(redisClient, a.ID)
+	if err != nil {
+		t.Fatalf("reading auction: %v", err)
+	}
+	if a.Status != "sold" {
+		t.Errorf("status = %q, want sold", a.Status)
+	}
+	if !hasFish("carol", a.Fish.ID) || hasFish("alice", a.Fish.ID) {
+		t.Error("fish was not delivered to the high bidder")
+	}
+	if n, worth := fishCount("carol"); n != 1 || worth != 300 {
+		t.Errorf("buyer inventory = %v fish worth %v, want 1 worth 300", n, worth)
+	}
+	if got := DBGetWallet("alice"); got != 200 {
+		t.Errorf("seller wallet = %v, want 200", got)
+	}
+	if got := DBGetWallet("carol"); got != 300 {
+		t.Errorf("buyer wallet = %v, want 300", got)
+	}
+	if n := redisClient.ZCard(AuctionEndKey).Val(); n != 0 {
+		t.Errorf("%v auctions are still waiting to settle", n)
+	}
+}
+
+func TestSettleAuctionReturnsUnsoldFish(t *testing.T) {
+	newTestRedis(t)
+	a := listTestAuction(t, "alice", InvFish{Name: "Marlin", Price: 300, Tier: 5}, 100)
+
+	CurrentTime = a.Ends.Add(time.Minute)
+	settleAuctions()
+
+	a, err := getAuction(redisClient, a.ID)
+	if err != nil {
+		t.Fatalf("reading auction: %v", err)
+	}
+	if a.Status != "unsold" {
+		t.Errorf("status = %q, want unsold", a.Status)
+	}
+	if !hasFish("alice", a.Fish.ID) {
+		t.Error("unsold fish was not returned to the seller")
+	}
+	if n, worth := fishCount("alice"); n != 1 || worth != 300 {
+		t.Errorf("seller inventory = %v fish worth %v, want 1 worth 300", n, worth)
+	}
+	if got := DBGetWallet("alice"); got != 0 {
+		t.Errorf("seller wallet = %v, want 0", got)
+	}
+}
+
+func TestSettleAuctionOnlyOnce(t *testing.T) {
+	newTestRedis(t)
+	a := listTestAuction(t, "alice", InvFish{Name: "Marlin", Price: 300, Tier: 5}, 100)
+	DBAddWallet("bob", 500)
+	if _, err := DBBidAuction("bob", a.ID, 150); err != nil {
+		t.Fatalf("bidding: %v", err)
+	}
+
+	CurrentTime = a.Ends.Add(time.Minute)
+	if _, err := settleAuction(a.ID); err != nil {
+		t.Fatalf("settling auction: %v", err)
+	}
+	if _, err := settleAuction(a.ID); err == nil {
+		t.Error("the auction was settled twice")
+	}
+	if got := DBGetWallet("alice"); got != 150 {
+		t.Errorf("seller wallet = %v, want 150", got)
+	}
+	if n, _ := fishCount("bob"); n != 1 {
+		t.Errorf("buyer has %v fish, want 1", n)
+	}
+	if _, err := DBBidAuction("bob", a.ID, 200); err == nil {
+		t.Error("a bid was accepted on a settled auction")
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// DBBatchCast makes up to n casts from the rates of a cast and applies all of their outcomes in a single transaction,
+// the batch ends early once the user runs out of bait or fish inventory space
+func DBBatchCast(c cast, n int) (BatchResult, error) {
+	res, err := applyCasts(c, n, true, "")
+	if err == nil && res.Exp > 0 {
+		DBGiveGuildScore(c.user.ID, res.Exp, c.guildID)
+	}
+	return res, err
+}
+
+// fishIDs are ids allocated for the fish of a batch, they are kept across retries of its transaction
+// so a retry only allocates the ids it needs beyond those it already has
+type fishIDs []string
+
+// take returns n ids, allocating any that are missing
+func (ids *fishIDs) take(n int) ([]string, error) {
+	if need := n - len(*ids); need > 0 {
+		last, err := redisClient.IncrBy(FishIDKey, int64(need)).Result()
+		if err != nil {
+			return nil, err
+		}
+		for i := need - 1; i >= 0; i-- {
+			*ids = append(*ids, strconv.FormatInt(last-int64(i), 10))
+		}
+	}
+	return (*ids)[:n], nil
+}
+
+// applyCasts rolls up to n casts and applies their outcomes in a single transaction,
+// active casts are made by the user and use bait, wear their gear and count towards their exp, luck, stats and cooldown
+// while passive casts only land what they catch. A passive batch collecting a net is given the net as it is stored,
+// the batch fails if the net has changed and removes it when it commits
+func applyCasts(c cast, n int, active bool, net string) (BatchResult, error) {
+	userID := c.user.ID
+	inv := DBGetInventory(userID)
+	baitTier := DBGetCurrentBaitTier(userID)
+	userTier := ExpToTier(DBGetGlobalScore(userID))
+	capacity := DBGetInvCapacity(userID)
+	exp := eventExpMultiplier(c.loc) * c.buffs.multiplier("exp") * prestigeExpMultiplier(userID) * DBGetClubPerks(c.guildID).multiplier()
+	cooldown := c.cooldown()
+	var res BatchResult
+	var ids fishIDs
+	keys := []string{BaitInvKey(userID), FishInvKey(userID), LuckKey(userID)}
+	if net != "" {
+		keys = append(keys, NetKey(userID))
+	}
+	err := watchTx(func(tx *redis.Tx) error {
+		res = BatchResult{Results: []BatchCast{}}
+		if net != "" && tx.Get(NetKey(userID)).Val() != net {
+			return errors.New("Your net has already been collected")
+		}
+		bait := baitAmount(tx, userID, baitTier)
+		space := capacity - invSize(tx, userID)
+		if space < 1 {
+			return errors.New("Your fish inventory is full")
+		}
+		luck := Luck{}
+		if active {
+			luck = luckOf(tx, userID)
+		}
+		fish := []InvFish{}
+		for res.Casts < n {
+			if active && bait < 1 {
+				res.Stopped = "You ran out of bait"
+				break
+			}
+			if space < 1 {
+				res.Stopped = "Your fish inventory is full"
+				break
+			}
+			// the rates of the cast already include the pity it was made with
+			pity := luck.pity() - c.pity
+			_, e := fishCatch(c.bite+pity, c.catch+pity, c.fish)
+			luck = luck.record(e)
+			res.Casts++
+			switch e {
+			case "fish":
+				f := fishOfTier(selectTier(userTier), c.loc)
+				fish = append(fish, f)
+				space--
+				res.Fish++
+				res.Worth += f.Price
+				if active {
+					bait--
+					res.BaitUsed++
+					res.Exp += exp * luck.streakMultiplier()
+				}
+			case "garbage":
+				res.Garbage++
+				res.Worth += garbageWorth
+			case "catch":
+				res.Fails++
+				if active {
+					bait--
+					res.BaitUsed++
+				}
+			default:
+				res.Fails++
+			}
+			res.Results = append(res.Results, BatchCast{Outcome: e})
+		}
+		if res.Casts == 0 {
+			return errors.New(res.Stopped)
+		}
+		if len(fish) > 0 {
+			taken, err := ids.take(len(fish))
+			if err != nil {
+				return err
+			}
+			for i := range fish {
+				fish[i].ID = taken[i]
+			}
+			i := 0
+			for j := range res.Results {
+				if res.Results[j].Outcome == "fish" {
+					res.Results[j].Fish = &fish[i]
+					i++
+				}
+			}
+		}
+		if active {
+			res.Luck = luck
+			res.Cooldown = int64((cooldown * time.Duration(res.Casts)).Seconds())
+		}
+		_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+			if net != "" {
+				pipe.Del(NetKey(userID))
+			}
+			queueAddFish(pipe, userID, fish)
+			if res.Garbage > 0 {
+				pipe.HIncrBy(FishInvKey(userID), "garbage", int64(res.Garbage))
+				pipe.HIncrBy(FishInvKey(userID), "worth", int64(res.Garbage*garbageWorth))
+			}
+			if !active {
+				return nil
+			}
+			if res.BaitUsed > 0 {
+				pipe.HIncrBy(BaitInvKey(userID), strconv.Itoa(baitTier), int64(-res.BaitUsed))
+			}
+			if res.Exp > 0 {
+				pipe.ZIncrBy(ScoreGlobalKey, res.Exp, userID)
+				queueScoreWindows(pipe, "", userID, res.Exp)
+			}
+			for _, key := range []string{GlobalStatsKey(userID), GuildStatsKey(userID, c.guildID)} {
+				pipe.HIncrBy(key, "casts", int64(res.Casts))
+				if res.Garbage > 0 {
+					pipe.HIncrBy(key, "garbage", int64(res.Garbage))
+				}
+			}
+			pipe.HMSet(LuckKey(userID), map[string]interface{}{"fails": luck.Fails, "streak": luck.Streak})
+			queueWear(pipe, userID, inv, res.Casts)
+			pipe.Set(RateLimitKey("fishy", userID), "", cooldown*time.Duration(res.Casts))
+			return nil
+		})
+		return err
+	}, keys...)
+	return res, err
+}
+
+// recordBatch emits the activities of a batch and records its catches
+func (c cast) recordBatch(res BatchResult) {
+	for _, b := range res.Results {
+		emitActivity(Activity{Type: "cast", UserID: c.user.ID, GuildID: c.guildID, Location: c.loc})
+		switch b.Outcome {
+		case "fish":
+			emitActivity(Activity{Type: "fish", UserID: c.user.ID, GuildID: c.guildID, Location: c.loc, Fish: *b.Fish})
+		case "garbage":
+			emitActivity(Activity{Type: "garbage", UserID: c.user.ID, GuildID: c.guildID, Location: c.loc})
+		default:
+			emitActivity(Activity{Type: "fail", UserID: c.user.ID, GuildID: c.guildID, Location: c.loc})
+		}
+	}
+	c.recordCatches(res)
+}
+
+// recordCatches updates the stats and records that follow from the fish caught in a batch,
+// these are kept out of the transaction the same way a single cast updates them in the background
+func (c cast) recordCatches(res BatchResult) {
+	go func() {
+		for _, b := range res.Results {
+			if b.Fish == nil {
+				continue
+			}
+			DBIncrGlobalAvgFishStats(c.user.ID, b.Fish.Size)
+			DBIncrGuildAvgFishStats(c.user.ID, c.guildID, b.Fish.Size)
+			DBRecordFishdex(c.user.ID, *b.Fish)
+			if _, err := DBUpdateRecords(c.user.ID, c.guildID, *b.Fish); err != nil {
+				logError("Unable to update records", err)
+			}
+			if _, err := DBSetLocDensity(c.loc, c.user.ID); err != nil {
+				logError("Unable to set location density", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// buffKinds are the effects a buff can have,
+// bite and catch add to their rates, exp and cooldown multiply and nogarbage turns every catch into a fish
+var buffKinds = map[string]bool{
+	"exp":       true,
+	"bite":      true,
+	"catch":     true,
+	"cooldown":  true,
+	"nogarbage": true,
+}
+
+func (g BuffGrant) validate() error {
+	if !buffKinds[g.Kind] {
+		return fmt.Errorf("Invalid buff kind %s", g.Kind)
+	}
+	if g.Value <= 0 {
+		return errors.New("Buff values must be positive")
+	}
+	if g.Kind == "cooldown" && g.Value > 1 {
+		return errors.New("Cooldown buffs cannot be more than 1")
+	}
+	return nil
+}
+
+// stronger reports whether a buff has a stronger effect than another of the same kind
+func (b Buff) stronger(o Buff) bool {
+	if b.Kind == "cooldown" {
+		return b.Value < o.Value
+	}
+	return b.Value > o.Value
+}
+
+// eventBuffs returns the buffs of every event active at a location, they last until the event ends
+func eventBuffs(location string) []Buff {
+	buffs := []Buff{}
+	for _, e := range ActiveEvents(location) {
+		for _, g := range e.Buffs {
+			if g.validate() == nil {
+				buffs = append(buffs, Buff{Kind: g.Kind, Value: g.Value, Source: e.Name, Expires: e.End})
+			}
+		}
+	}
+	return buffs
+}
+
+// DBGrantBuff gives a user a buff, a buff of a kind the user already has keeps the stronger value and the later expiry
+func DBGrantBuff(userID, source string, g BuffGrant) error {
+	return watchTx(func(tx *redis.Tx) error {
+		_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+			return queueBuff(tx, pipe, userID, source, g)
+		})
+		return err
+	}, BuffKey(userID, g.Kind))
+}
+
+// queueBuff queues granting a user a buff, their current buff of its kind is read with the given client
+// so a buff can be granted inside a transaction that watches its key
+func queueBuff(c redis.Cmdable, pipe redis.Pipeliner, userID, source string, g BuffGrant) error {
+	if err := g.validate(); err != nil {
+		return err
+	}
+	if g.Duration < 1 {
+		return errors.New("Buff duration must be positive")
+	}
+	key := BuffKey(userID, g.Kind)
+	b := Buff{Kind: g.Kind, Value: g.Value, Source: source, Expires: CurrentTime.Add(time.Duration(g.Duration) * time.Minute)}
+	if data, err := c.Get(key).Result(); err == nil {
+		var cur Buff
+		if err := json.Unmarshal([]byte(data), &cur); err == nil && CurrentTime.Before(cur.Expires) {
+			if cur.stronger(b) {
+				b.Value, b.Source = cur.Value, cur.Source
+			}
+			if cur.Expires.After(b.Expires) {
+				b.Expires = cur.Expires
+			}
+		}
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	pipe.Set(key, data, b.Expires.Sub(CurrentTime))
+	return nil
+}
+
+// buffSet is every buff active on a user, read once so a cast can consult it without going back to the database
+type buffSet []Buff
+
+// DBGetBuffs returns every buff active on a user, including those of events at their location,
+// a buff is stored until it expires so nothing has to be cleaned up here
+func DBGetBuffs(userID string) buffSet {
+	buffs := buffSet(eventBuffs(DBGetLocation(userID)))
+	keys := []string{}
+	for kind := range buffKinds {
+		keys = append(keys, BuffKey(userID, kind))
+	}
+	stored, err := redisClient.MGet(keys...).Result()
+	if err != nil {
+		logError("Unable to retrieve buffs", err)
+	}
+	for _, v := range stored {
+		data, ok := v.(string)
+		if !ok {
+			continue
+		}
+		var b Buff
+		if err := json.Unmarshal([]byte(data), &b); err != nil {
+			logError("Unable to unmarshal buff", err)
+			continue
+		}
+		if CurrentTime.Before(b.Expires) {
+			buffs = append(buffs, b)
+		}
+	}
+	for i := range buffs {
+		buffs[i].Remaining = int64(buffs[i].Expires.Sub(CurrentTime).Seconds())
+	}
+	sort.Slice(buffs, func(i, j int) bool { return buffs[i].Expires.Before(buffs[j].Expires) })
+	return buffs
+}
+
+// bonus returns the combined bonus to a rate from the buffs of a kind
+func (bs buffSet) bonus(kind string) int64 {
+	bonus := float64(0)
+	for _, b := range bs {
+		if b.Kind == kind {
+			bonus += b.Value
+		}
+	}
+	return int64(bonus)
+}
+
+// multiplier returns the combined multiplier of the buffs of a kind
+func (bs buffSet) multiplier(kind string) float64 {
+	m := float64(1)
+	for _, b := range bs {
+		if b.Kind == kind {
+			m *= b.Value
+		}
+	}
+	return m
+}
+
+// cooldown returns the multiplier of the cooldown buffs, it can only ever shorten a cooldown
+func (bs buffSet) cooldown() float64 {
+	m := bs.multiplier("cooldown")
+	if m <= 0 || m > 1 {
+		return 1
+	}
+	return m
+}
+
+// has reports whether there is an active buff of a kind
+func (bs buffSet) has(kind string) bool {
+	for _, b := range bs {
+		if b.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// apply adds the effects of the buffs to the rates of a cast
+func (bs buffSet) apply(c *cast) {
+	c.buffs = bs
+	c.bite += bs.bonus("bite")
+	c.catch += bs.bonus("catch")
+	if bs.has("nogarbage") {
+		c.fish = 100
+	}
+}
+
+// DBUseConsumable uses up one of a users consumables and grants its buffs
+func DBUseConsumable(userID, consumableID string) (Consumable, error) {
+	var c Consumable
+	for _, e := range Recipes.Consumables {
+		if e.ID == consumableID {
+			c = e
+		}
+	}
+	if c.ID == "" {
+		return c, fmt.Errorf("Consumable %s does not exist", consumableID)
+	}
+	key := ConsumableKey(userID)
+	err := watchTx(func(tx *redis.Tx) error {
+		n, _ := strconv.Atoi(tx.HGet(key, c.ID).Val())
+		if n < 1 {
+			return fmt.Errorf("You do not have any %s", c.Name)
+		}
+		_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.HIncrBy(key, c.ID, -1)
+			return nil
+		})
+		return err
+	}, key)
+	if err != nil {
+		return c, err
+	}
+	for _, g := range c.Buffs {
+		if err := DBGrantBuff(userID, c.Name, g); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
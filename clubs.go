@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// clubLevel returns the level a club pool has reached and the bonuses it unlocks,
+// only the bonuses of the highest level reached apply
+func clubLevel(exp float64) (int, ClubLevel) {
+	level, perks := 0, ClubLevel{}
+	for i, l := range Clubs.Levels {
+		if exp >= l.Exp {
+			level, perks = i+1, l
+		}
+	}
+	return level, perks
+}
+
+// multiplier returns the exp multiplier of a club level
+func (l ClubLevel) multiplier() float64 {
+	return 1 + l.ExpBonus
+}
+
+func clubExp(guildID string) float64 {
+	exp, _ := strconv.ParseFloat(redisClient.HGet(ClubKey(guildID), "exp").Val(), 64)
+	return exp
+}
+
+// DBGetClubPerks returns the bonuses a guilds club has unlocked for its members
+func DBGetClubPerks(guildID string) ClubLevel {
+	_, perks := clubLevel(clubExp(guildID))
+	return perks
+}
+
+func clubWeek(t time.Time) string {
+	return questPeriodKey("weekly", t)
+}
+
+// clubWeekExpiry returns when the keys of a clubs week can be removed, the previous week is kept until the next one ends
+func clubWeekExpiry(t time.Time) time.Time {
+	return questPeriodEnd("weekly", t).AddDate(0, 0, 7)
+}
+
+// feedClub adds exp a member earned to their guilds club pool and weekly goals, rewarding goals as they are reached
+func feedClub(userID string, amt float64, guildID string) {
+	if amt <= 0 {
+		return
+	}
+	week := clubWeek(CurrentTime)
+	expires := clubWeekExpiry(CurrentTime)
+	goals := ClubGoalKey(guildID, week)
+	var total *redis.FloatCmd
+	_, err := redisClient.Pipelined(func(pipe redis.Pipeliner) error {
+		pipe.HIncrByFloat(ClubKey(guildID), "exp", amt)
+		pipe.ZIncrBy(ClubWeekKey(guildID, week), amt, userID)
+		pipe.ExpireAt(ClubWeekKey(guildID, week), expires)
+		total = pipe.HIncrByFloat(goals, "exp", amt)
+		pipe.ExpireAt(goals, expires)
+		return nil
+	})
+	if err != nil {
+		logError("Unable to feed club", err)
+		return
+	}
+	now := total.Val()
+	for _, g := range Clubs.Goals {
+		if now-amt < g.Target && now >= g.Target {
+			redisClient.HSet(goals, "goal:"+g.Name, CurrentTime.Unix())
+			if g.Reward > 0 {
+				redisClient.HIncrByFloat(ClubKey(guildID), "exp", g.Reward)
+			}
+		}
+	}
+}
+
+func clubMembers(key string) []ClubMember {
+	members := []ClubMember{}
+	z, err := redisClient.ZRevRangeWithScores(key, 0, 9).Result()
+	if err != nil {
+		logError("Unable to retrieve club members", err)
+		return members
+	}
+	for i, e := range z {
+		user := e.Member.(string)
+		members = append(members, ClubMember{int64(i + 1), user, DBGetTrackedUser(user), e.Score})
+	}
+	return members
+}
+
+// DBGetClub returns the profile of a guilds club with its progress this week and its top contributors
+func DBGetClub(guildID string) Club {
+	data := redisClient.HGetAll(ClubKey(guildID)).Val()
+	exp, _ := strconv.ParseFloat(data["exp"], 64)
+	c := Club{
+		GuildID:     guildID,
+		Name:        data["name"],
+		Description: data["description"],
+		Exp:         exp,
+		Top:         clubMembers(ScoreGuildKey(guildID)),
+	}
+	c.Level, c.Perks = clubLevel(exp)
+	if c.Level < len(Clubs.Levels) {
+		c.NextLevel = Clubs.Levels[c.Level].Exp
+	}
+	week := clubWeek(CurrentTime)
+	goals := redisClient.HGetAll(ClubGoalKey(guildID, week)).Val()
+	c.Week.Exp, _ = strconv.ParseFloat(goals["exp"], 64)
+	c.Week.Ends = questPeriodEnd("weekly", CurrentTime)
+	c.Week.Top = clubMembers(ClubWeekKey(guildID, week))
+	c.Week.Goals = []ClubGoalStatus{}
+	for _, g := range Clubs.Goals {
+		_, done := goals["goal:"+g.Name]
+		c.Week.Goals = append(c.Week.Goals, ClubGoalStatus{g, done})
+	}
+	return c
+}
+
+// DBEditClub changes the name and description of a guilds club
+func DBEditClub(guildID string, req ClubRequest) (Club, error) {
+	if req.Name == "" {
+		return Club{}, errors.New("Clubs need a name")
+	}
+	if len(req.Name) > 32 || len(req.Description) > 256 {
+		return Club{}, errors.New("Club names can be 32 characters and descriptions 256")
+	}
+	err := redisClient.HMSet(ClubKey(guildID), map[string]interface{}{
+		"name":        req.Name,
+		"description": req.Description,
+	}).Err()
+	if err != nil {
+		return Club{}, err
+	}
+	return DBGetClub(guildID), nil
+}
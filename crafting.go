@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/go-redis/redis"
+)
+
+// garbageWorth is how much a single piece of garbage adds to a users inventory worth
+const garbageWorth = 5
+
+// matches reports whether a fish can be used as a recipe ingredient
+func (rf RecipeFish) matches(f InvFish) bool {
+	if rf.Species != "" && rf.Species != f.Name {
+		return false
+	}
+	if rf.Tier != 0 && rf.Tier != f.Tier {
+		return false
+	}
+	if rf.Location != "" && rf.Location != f.Location {
+		return false
+	}
+	return true
+}
+
+// specificity is how many fields of an ingredient narrow down which fish it accepts
+func (rf RecipeFish) specificity() int {
+	n := 0
+	if rf.Species != "" {
+		n++
+	}
+	if rf.Tier != 0 {
+		n++
+	}
+	if rf.Location != "" {
+		n++
+	}
+	return n
+}
+
+func getRecipe(recipeID string) (Recipe, error) {
+	for _, r := range Recipes.Recipes {
+		if r.ID == recipeID {
+			return r, nil
+		}
+	}
+	return Recipe{}, fmt.Errorf("Recipe %s does not exist", recipeID)
+}
+
+func validConsumable(id string) bool {
+	for _, c := range Recipes.Consumables {
+		if c.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// pickIngredients chooses the fish a recipe uses up, the most specific ingredients pick first
+// and the cheapest matching fish are always used so prized catches are kept
+func pickIngredients(r Recipe, fish []InvFish) ([]InvFish, error) {
+	sort.Slice(fish, func(i, j int) bool { return fish[i].Price < fish[j].Price })
+	reqs := append([]RecipeFish{}, r.Fish...)
+	sort.SliceStable(reqs, func(i, j int) bool { return reqs[i].specificity() > reqs[j].specificity() })
+	used := map[string]bool{}
+	picked := []InvFish{}
+	for _, req := range reqs {
+		n := 0
+		for _, f := range fish {
+			if n >= req.Amount {
+				break
+			}
+			if !used[f.ID] && req.matches(f) {
+				used[f.ID] = true
+				picked = append(picked, f)
+				n++
+			}
+		}
+		if n < req.Amount {
+			return nil, fmt.Errorf("Not enough fish for %s", r.Name)
+		}
+	}
+	return picked, nil
+}
+
+// DBGetConsumables returns how many of each consumable a user has
+func DBGetConsumables(userID string) map[string]int {
+	items := map[string]int{}
+	for id, n := range redisClient.HGetAll(ConsumableKey(userID)).Val() {
+		amt, err := strconv.Atoi(n)
+		if err != nil || amt < 1 {
+			continue
+		}
+		items[id] = amt
+	}
+	return items
+}
+
+// DBCraft uses up the ingredients of a recipe and gives its output in a single transaction,
+// failing without changing anything if the ingredients are missing or the bait wouldn't fit
+func DBCraft(userID, recipeID string) (CraftResult, error) {
+	r, err := getRecipe(recipeID)
+	if err != nil {
+		return CraftResult{}, err
+	}
+	for id := range r.Output.Consumables {
+		if !validConsumable(id) {
+			return CraftResult{}, fmt.Errorf("Recipe %s makes an unknown consumable", r.ID)
+		}
+	}
+	res := CraftResult{Recipe: r, Garbage: r.Garbage}
+	cap := DBGetBaitCapacity(userID)
+	err = watchTx(func(tx *redis.Tx) error {
+		garbage, _ := strconv.Atoi(tx.HGet(FishInvKey(userID), "garbage").Val())
+		if garbage < r.Garbage {
+			return errors.New("Not enough garbage")
+		}
+		used, err := pickIngredients(r, storedFish(tx, userID))
+		if err != nil {
+			return err
+		}
+		for _, b := range r.Output.Bait {
+			if baitAmount(tx, userID, b.Tier)+b.Amount > cap {
+				return fmt.Errorf("Not enough room for the tier %v bait", b.Tier)
+			}
+		}
+		res.Used = used
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			queueRemoveFish(pipe, userID, used)
+			if r.Garbage > 0 {
+				pipe.HIncrBy(FishInvKey(userID), "garbage", int64(-r.Garbage))
+				pipe.HIncrBy(FishInvKey(userID), "worth", int64(-r.Garbage*garbageWorth))
+			}
+			for _, b := range r.Output.Bait {
+				pipe.HIncrBy(BaitInvKey(userID), strconv.Itoa(b.Tier), int64(b.Amount))
+			}
+			for id, n := range r.Output.Consumables {
+				pipe.HIncrBy(ConsumableKey(userID), id, int64(n))
+			}
+			return nil
+		})
+		return err
+	}, CatchesKey(userID), FishInvKey(userID), BaitInvKey(userID))
+	return res, err
+}
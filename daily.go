@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// dailyClaimScript starts a new claim if the interval since the last one has passed,
+// the streak continues when the claim is made within the grace window after that.
+// It returns the new streak, or 0 and the seconds remaining until the next claim
+var dailyClaimScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local grace = tonumber(ARGV[3])
+local last = tonumber(redis.call("HGET", KEYS[1], "last"))
+local streak = tonumber(redis.call("HGET", KEYS[1], "streak")) or 0
+if last ~= nil and now - last < interval then
+	return {0, interval - (now - last)}
+end
+if last == nil or now - last > interval + grace then
+	streak = 0
+end
+streak = streak + 1
+redis.call("HMSET", KEYS[1], "last", now, "streak", streak)
+return {streak, 0}
+`)
+
+func dailyInterval() time.Duration {
+	if Daily.IntervalHours < 1 {
+		return 24 * time.Hour
+	}
+	return time.Duration(Daily.IntervalHours) * time.Hour
+}
+
+func dailyGrace() time.Duration {
+	return time.Duration(Daily.GraceHours) * time.Hour
+}
+
+// dailyReward returns the reward for a streak, the last reward repeats once the table runs out
+func dailyReward(streak int) Reward {
+	if len(Daily.Rewards) == 0 {
+		return Reward{}
+	}
+	if streak > len(Daily.Rewards) {
+		streak = len(Daily.Rewards)
+	}
+	return Daily.Rewards[streak-1]
+}
+
+// DBGetDaily returns a users daily reward streak
+func DBGetDaily(userID string) DailyStatus {
+	data := redisClient.HGetAll(DailyKey(userID)).Val()
+	last, err := strconv.ParseInt(data["last"], 10, 64)
+	if err != nil {
+		return DailyStatus{0, CurrentTime, CurrentTime, nil}
+	}
+	streak, _ := strconv.Atoi(data["streak"])
+	lastClaim := time.Unix(last, 0).UTC()
+	expiry := lastClaim.Add(dailyInterval() + dailyGrace())
+	if CurrentTime.After(expiry) {
+		streak = 0
+	}
+	return DailyStatus{streak, lastClaim.Add(dailyInterval()), expiry, nil}
+}
+
+// DBClaimDaily claims a users daily reward and pays it out,
+// if it has already been claimed it returns the time left until the next claim
+func DBClaimDaily(userID string) (DailyStatus, time.Duration, error) {
+	res, err := dailyClaimScript.Run(redisClient, []string{DailyKey(userID)},
+		CurrentTime.Unix(), int64(dailyInterval().Seconds()), int64(dailyGrace().Seconds()),
+	).Result()
+	if err != nil {
+		return DailyStatus{}, 0, err
+	}
+	vals, ok := res.([]interface{})
+	if !ok || len(vals) != 2 {
+		return DailyStatus{}, 0, fmt.Errorf("unexpected daily claim result %v", res)
+	}
+	streak, wait := int(vals[0].(int64)), time.Duration(vals[1].(int64))*time.Second
+	if streak == 0 {
+		return DailyStatus{}, wait, nil
+	}
+	rw := dailyReward(streak)
+	if err := DBGiveReward(userID, rw); err != nil {
+		return DailyStatus{}, 0, err
+	}
+	return DailyStatus{
+		Streak:       streak,
+		NextClaim:    CurrentTime.Add(dailyInterval()),
+		StreakExpiry: CurrentTime.Add(dailyInterval() + dailyGrace()),
+		Reward:       &rw,
+	}, 0, nil
+}
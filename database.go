@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"reflect"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -22,7 +23,8 @@ var redisClient *redis.Client
 
 const locDensityExpiration time.Duration = 3 * time.Hour
 
-func init() {
+// setupDatabase loads the configs and connects to redis, it is called from main so tests can use their own client
+func setupDatabase() {
 	GetConfigs()
 	// client, err := elastic.NewClient(elastic.SetURL("http://10.0.0.2:9200"))
 	// if err != nil {
@@ -253,8 +255,11 @@ func DBGetBiteRate(userID string, locDen UserLocDensity, loc string) int64 {
 
 //
 func DBGetCatchRate(userID string) (int64, error) {
-	inv := DBGetInventory(userID)
-	switch inv.Rod.Current {
+	rod := DBGetInventory(userID).Rod.Current
+	if itemBroken(userID, "rod", rod) {
+		return brokenRate, nil
+	}
+	switch rod {
 	case 200:
 		return 50, nil
 	case 201:
@@ -271,8 +276,11 @@ func DBGetCatchRate(userID string) (int64, error) {
 
 //
 func DBGetFishRate(userID string) (int64, error) {
-	inv := DBGetInventory(userID)
-	switch inv.Hook.Current {
+	hook := DBGetInventory(userID).Hook.Current
+	if itemBroken(userID, "hook", hook) {
+		return brokenRate, nil
+	}
+	switch hook {
 	case 300:
 		return 50, nil
 	case 301:
@@ -323,6 +331,8 @@ var defaultUserItems = UserItems{
 	UserItem{0, []int{}},
 	UserItem{0, []int{}},
 	UserItem{0, []int{}},
+	UserItem{0, []int{}},
+	UserItem{0, []int{}},
 }
 
 // DBInventoryCheckExists makes sure a user has an inventory key before modifying it
@@ -331,7 +341,7 @@ func DBInventoryCheckExists(userID string) bool {
 	if keyExists(key) {
 		return true
 	}
-	redisClient.HMSet(key, map[string]interface{}{"bait": 0, "rod": 0, "hook": 0, "vehicle": 0, "baitbox": 0})
+	redisClient.HMSet(key, map[string]interface{}{"bait": 0, "rod": 0, "hook": 0, "vehicle": 0, "baitbox": 0, "aquarium": 0, "autofisher": 0})
 	return false
 }
 
@@ -348,7 +358,11 @@ func DBGetGlobalScore(userID string) float64 {
 
 // DBGiveGlobalScore increments a users global exp
 func DBGiveGlobalScore(userID string, amt float64) error {
-	err := redisClient.ZIncrBy(ScoreGlobalKey, amt, userID).Err()
+	_, err := redisClient.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.ZIncrBy(ScoreGlobalKey, amt, userID)
+		queueScoreWindows(pipe, "", userID, amt)
+		return nil
+	})
 	if err != nil {
 		logError("Unable to increment global exp", err)
 		return err
@@ -369,6 +383,30 @@ func DBGetGlobalScoreRank(u string) (int64, float64) {
 	return redisClient.ZRevRank(ScoreGlobalKey, u).Val(), redisClient.ZScore(ScoreGlobalKey, u).Val()
 }
 
+// DBGetBoardPage gets a specific page of a leaderboard
+func DBGetBoardPage(key string, p int) ([]redis.Z, error) {
+	if p < 1 {
+		p = 1
+	}
+	return redisClient.ZRevRangeWithScores(key, int64(p-1)*10, int64(p*10)-1).Result()
+}
+
+// DBGetBoardRank returns a users ranking on a leaderboard
+func DBGetBoardRank(key, u string) (int64, float64) {
+	return redisClient.ZRevRank(key, u).Val(), redisClient.ZScore(key, u).Val()
+}
+
+// guildBoard stores the entries of a global board that belong to members of a guild in dest,
+// guild membership comes from the guild exp board
+func guildBoard(dest, guildID, key string) error {
+	_, err := redisClient.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.ZInterStore(dest, redis.ZStore{Weights: []float64{0, 1}}, ScoreGuildKey(guildID), key)
+		pipe.Expire(dest, time.Minute)
+		return nil
+	})
+	return err
+}
+
 // DBGetGuildScore gets a users global xp for a specific user
 func DBGetGuildScore(userID string, guildID string) float64 {
 	exp, err := redisClient.ZScore(ScoreGuildKey(guildID), userID).Result()
@@ -382,11 +420,16 @@ func DBGetGuildScore(userID string, guildID string) float64 {
 
 // DBGiveGuildScore increments a users global exp
 func DBGiveGuildScore(userID string, amt float64, guildID string) error {
-	err := redisClient.ZIncrBy(ScoreGuildKey(guildID), amt, userID).Err()
+	_, err := redisClient.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.ZIncrBy(ScoreGuildKey(guildID), amt, userID)
+		queueScoreWindows(pipe, guildID, userID, amt)
+		return nil
+	})
 	if err != nil {
 		logError("Unable to increment guild exp", err)
 		return err
 	}
+	feedClub(userID, amt, guildID)
 	return nil
 }
 
@@ -423,12 +466,35 @@ func DBEditItemTier(userID string, item string, tier string) error {
 	return fmt.Errorf("Item %s not allowed", item)
 }
 
-var allowedItems = map[string]bool{
+// equipableItems are the item categories that can be switched to any owned item
+var equipableItems = map[string]bool{
 	"rod":     true,
 	"hook":    true,
 	"vehicle": true,
 	"baitbox": true,
-	"bait":    true,
+}
+
+// DBEquipItem switches a users current item of a category to another item they own
+func DBEquipItem(userID, category string, item int) error {
+	if !equipableItems[category] {
+		return fmt.Errorf("Item %s cannot be equipped", category)
+	}
+	for _, o := range DBGetOwnedItems(userID, category) {
+		if o == item {
+			return DBEditItemTier(userID, category, strconv.Itoa(item))
+		}
+	}
+	return fmt.Errorf("You do not own that %s", category)
+}
+
+var allowedItems = map[string]bool{
+	"rod":        true,
+	"hook":       true,
+	"vehicle":    true,
+	"baitbox":    true,
+	"bait":       true,
+	"aquarium":   true,
+	"autofisher": true,
 }
 
 // DBEditItemTiersSafe changes a users item tiers and checks for progression
@@ -506,9 +572,10 @@ func DBCheckBlacklist(userID string) bool {
 	return keyExists(BlackListKey(userID))
 }
 
-// DBStartGatherBait starts the bait gathering timeout
-func DBStartGatherBait(userID string) error {
-	return redisClient.Set(GatherBaitKey(userID), "", GatherBaitTimeout).Err()
+// DBStartGatherBait starts the bait gathering timeout, cooldown buffs shorten it
+func DBStartGatherBait(userID string) (time.Duration, error) {
+	timeout := time.Duration(float64(GatherBaitTimeout) * DBGetBuffs(userID).cooldown()).Round(time.Minute)
+	return timeout, redisClient.Set(GatherBaitKey(userID), "", timeout).Err()
 }
 
 // DBCheckGatherBait checks to see whether or not a user is currently gathering bait
@@ -786,11 +853,129 @@ func DBGetInvSize(userID string) int {
 	return fish + legendary
 }
 
-//
-func DBSellFish(userID string) map[string]string {
+// DBSellFish empties a users fish inventory into their wallet at live market prices and lowers the prices of the species sold
+// in the same transaction, returning its totals and the yen paid for them
+func DBSellFish(userID string) (map[string]string, int) {
 	key := FishInvKey(userID)
-	fish := redisClient.HGetAll(key).Val()
-	redisClient.HMSet(key, map[string]interface{}{"fish": 0, "garbage": 0, "legendaries": 0, "worth": 0})
+	var fish map[string]string
+	var stored []InvFish
+	var yen int
+	err := watchTx(func(tx *redis.Tx) error {
+		fish = tx.HGetAll(key).Val()
+		stored = storedFish(tx, userID)
+		cur := getMultipliers(tx, MarketKey)
+		base, _ := strconv.Atoi(fish["worth"])
+		yen = int(float64(marketPrice(cur, base, stored)) * prestigeYenMultiplier(tx, userID))
+		_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.HMSet(key, map[string]interface{}{"fish": 0, "garbage": 0, "legendaries": 0, "worth": 0})
+			pipe.Del(CatchesKey(userID))
+			if yen > 0 {
+				pipe.IncrBy(WalletKey(userID), int64(yen))
+			}
+			queueMarketSale(pipe, cur, stored)
+			return nil
+		})
+		return err
+	}, key, CatchesKey(userID), MarketKey, PrestigeKey)
+	if err != nil {
+		logError("Unable to sell fish", err)
+		return map[string]string{}, 0
+	}
+	return fish, yen
+}
+
+// DBStoreFish stores a caught fish individually so it can be moved between inventories, returning it with its id
+func DBStoreFish(userID string, f InvFish) (InvFish, error) {
+	id, err := redisClient.Incr(FishIDKey).Result()
+	if err != nil {
+		return f, err
+	}
+	f.ID = strconv.FormatInt(id, 10)
+	data, err := json.Marshal(f)
+	if err != nil {
+		return f, err
+	}
+	return f, redisClient.HSet(CatchesKey(userID), f.ID, data).Err()
+}
+
+// fishByID returns individually stored fish from a users inventory, failing if any of them are missing
+func fishByID(c redis.Cmdable, userID string, ids []string) ([]InvFish, error) {
+	fish := []InvFish{}
+	if len(ids) == 0 {
+		return fish, nil
+	}
+	vals, err := c.HMGet(CatchesKey(userID), ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range vals {
+		data, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("Fish %s is not in the inventory of %s", ids[i], DBGetTrackedUser(userID))
+		}
+		var f InvFish
+		if err := json.Unmarshal([]byte(data), &f); err != nil {
+			return nil, err
+		}
+		fish = append(fish, f)
+	}
+	return fish, nil
+}
+
+// queueRemoveFish queues the removal of individually stored fish from a users inventory
+func queueRemoveFish(pipe redis.Pipeliner, userID string, fish []InvFish) {
+	if len(fish) == 0 {
+		return
+	}
+	worth := 0
+	for _, f := range fish {
+		pipe.HDel(CatchesKey(userID), f.ID)
+		worth += int(f.Price)
+	}
+	pipe.HIncrBy(FishInvKey(userID), "fish", int64(-len(fish)))
+	pipe.HIncrBy(FishInvKey(userID), "worth", int64(-worth))
+}
+
+// queueAddFish queues adding individually stored fish to a users inventory
+func queueAddFish(pipe redis.Pipeliner, userID string, fish []InvFish) {
+	if len(fish) == 0 {
+		return
+	}
+	worth := 0
+	for _, f := range fish {
+		data, _ := json.Marshal(f)
+		pipe.HSet(CatchesKey(userID), f.ID, data)
+		worth += int(f.Price)
+	}
+	pipe.HIncrBy(FishInvKey(userID), "fish", int64(len(fish)))
+	pipe.HIncrBy(FishInvKey(userID), "worth", int64(worth))
+}
+
+// DBGetStoredFish returns every individually stored fish in a users inventory, oldest first
+func DBGetStoredFish(userID string) []InvFish {
+	return storedFish(redisClient, userID)
+}
+
+func storedFish(c redis.Cmdable, userID string) []InvFish {
+	fish := []InvFish{}
+	data, err := c.HGetAll(CatchesKey(userID)).Result()
+	if err != nil {
+		logError("Unable to retrieve stored fish", err)
+		return fish
+	}
+	for _, e := range data {
+		var f InvFish
+		if err := json.Unmarshal([]byte(e), &f); err != nil {
+			logError("Unable to unmarshal stored fish", err)
+			continue
+		}
+		fish = append(fish, f)
+	}
+	sort.Slice(fish, func(i, j int) bool {
+		a, _ := strconv.Atoi(fish[i].ID)
+		b, _ := strconv.Atoi(fish[j].ID)
+		return a < b
+	})
 	return fish
 }
 
@@ -826,6 +1011,38 @@ func DBGetBaitCapacity(userID string) int {
 	return 25
 }
 
+// DBGetAquariumCapacity returns how many fish a users aquarium can display
+func DBGetAquariumCapacity(userID string) int {
+	inv := DBGetInventory(userID)
+	switch inv.Aquarium.Current {
+	case 601:
+		return 10
+	case 602:
+		return 15
+	case 603:
+		return 25
+	case 604:
+		return 40
+	}
+	return 5
+}
+
+// DBGetAutoFisherCasts returns how many casts a users auto-fisher can make in a single batch
+func DBGetAutoFisherCasts(userID string) int {
+	inv := DBGetInventory(userID)
+	switch inv.AutoFisher.Current {
+	case 701:
+		return 5
+	case 702:
+		return 10
+	case 703:
+		return 20
+	case 704:
+		return 50
+	}
+	return 0
+}
+
 //
 func DBGetBaitInv(userID string) BaitInv {
 	key := BaitInvKey(userID)
@@ -1058,6 +1275,116 @@ func DBGetCmdStats(cmd string) (CommandStatData, error) {
 	return CommandStatData{int(hour), int(day), totS}, nil
 }
 
+// baitAmount reads a users amount of a bait tier with the given client so it can be used inside transactions
+func baitAmount(c redis.Cmdable, userID string, tier int) int {
+	n, _ := strconv.Atoi(c.HGet(BaitInvKey(userID), strconv.Itoa(tier)).Val())
+	return n
+}
+
+// invSize reads how many fish a user is carrying with the given client so it can be used inside transactions
+func invSize(c redis.Cmdable, userID string) int {
+	key := FishInvKey(userID)
+	fish, _ := strconv.Atoi(c.HGet(key, "fish").Val())
+	legendary, _ := strconv.Atoi(c.HGet(key, "legendary").Val())
+	return fish + legendary
+}
+
+// watchRetries is how many times an optimistic transaction is attempted before giving up
+const watchRetries = 5
+
+// watchTx runs fn in a transaction watching keys, retrying when one of them changes before it commits
+func watchTx(fn func(*redis.Tx) error, keys ...string) error {
+	for i := 0; i < watchRetries; i++ {
+		err := redisClient.Watch(fn, keys...)
+		if err != redis.TxFailedErr {
+			return err
+		}
+	}
+	return redis.TxFailedErr
+}
+
+// DBGiveReward pays out a reward, any bait that doesn't fit in the users bait box is thrown away
+func DBGiveReward(userID string, rw Reward) error {
+	return watchTx(func(tx *redis.Tx) error {
+		_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+			return queueReward(tx, pipe, userID, rw)
+		})
+		return err
+	}, rewardKeys(userID, rw)...)
+}
+
+// rewardKeys returns the keys paying out a reward depends on
+func rewardKeys(userID string, rw Reward) []string {
+	keys := []string{BaitInvKey(userID)}
+	for _, g := range rw.Buffs {
+		keys = append(keys, BuffKey(userID, g.Kind))
+	}
+	return keys
+}
+
+// queueReward queues paying out a reward, the bait box and buffs are read with the given client
+// so a reward can be paid out inside a transaction that watches its rewardKeys
+func queueReward(c redis.Cmdable, pipe redis.Pipeliner, userID string, rw Reward) error {
+	cap := DBGetBaitCapacity(userID)
+	added := map[int]int{}
+	for _, b := range rw.Bait {
+		amt := b.Amount
+		if cur := baitAmount(c, userID, b.Tier) + added[b.Tier]; cur+amt > cap {
+			amt = cap - cur
+		}
+		if amt < 1 {
+			continue
+		}
+		added[b.Tier] += amt
+		pipe.HIncrBy(BaitInvKey(userID), strconv.Itoa(b.Tier), int64(amt))
+	}
+	if rw.Yen != 0 {
+		pipe.IncrBy(WalletKey(userID), int64(rw.Yen))
+	}
+	if rw.Exp != 0 {
+		pipe.ZIncrBy(ScoreGlobalKey, rw.Exp, userID)
+		queueScoreWindows(pipe, "", userID, rw.Exp)
+	}
+	for _, g := range rw.Buffs {
+		if err := queueBuff(c, pipe, userID, "reward", g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hsetMaxScript sets a hash field only if the new value is larger than the stored one
+var hsetMaxScript = redis.NewScript(`
+local cur = tonumber(redis.call("HGET", KEYS[1], ARGV[1]))
+if cur == nil or tonumber(ARGV[2]) > cur then
+	redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// hsetMax atomically raises a hash field to val and reports whether it changed
+func hsetMax(key, field string, val float64) (bool, error) {
+	n, err := hsetMaxScript.Run(redisClient, []string{key}, field, val).Int64()
+	return n == 1, err
+}
+
+// zaddMaxScript sets a sorted set members score only if the new score is larger than the stored one
+var zaddMaxScript = redis.NewScript(`
+local cur = tonumber(redis.call("ZSCORE", KEYS[1], ARGV[1]))
+if cur == nil or tonumber(ARGV[2]) > cur then
+	redis.call("ZADD", KEYS[1], ARGV[2], ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// zaddMax atomically raises a sorted set members score to val and reports whether it changed
+func zaddMax(key, member string, val float64) (bool, error) {
+	n, err := zaddMaxScript.Run(redisClient, []string{key}, member, val).Int64()
+	return n == 1, err
+}
+
 func keyExists(key string) bool {
 	return redisClient.Exists(key).Val() == int64(1)
 }
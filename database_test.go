@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis"
+)
+
+// newTestRedis points redisClient at an in-memory redis for the length of a test
+func newTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	prev := redisClient
+	redisClient = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	CurrentTime = time.Now().UTC()
+	t.Cleanup(func() {
+		redisClient.Close()
+		redisClient = prev
+	})
+	return mr
+}
+
+// giveTestFish stores a fish in a users inventory the same way a catch does
+func giveTestFish(t *testing.T, userID string, f InvFish) InvFish {
+	t.Helper()
+	f, err := DBStoreFish(userID, f)
+	if err != nil {
+		t.Fatalf("storing fish: %v", err)
+	}
+	redisClient.HIncrBy(FishInvKey(userID), "fish", 1)
+	redisClient.HIncrBy(FishInvKey(userID), "worth", int64(f.Price))
+	return f
+}
+
+// fishCount returns how many fish and how much worth a users inventory holds
+func fishCount(userID string) (int, int) {
+	inv := redisClient.HGetAll(FishInvKey(userID)).Val()
+	fish, _ := strconv.Atoi(inv["fish"])
+	worth, _ := strconv.Atoi(inv["worth"])
+	return fish, worth
+}
+
+// hasFish reports whether a fish is stored in a users inventory
+func hasFish(userID, fishID string) bool {
+	return redisClient.HExists(CatchesKey(userID), fishID).Val()
+}
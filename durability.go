@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis"
+)
+
+// wearingItems are the item categories that wear out as they are used
+var wearingItems = []string{"rod", "hook"}
+
+// brokenRate is the catch rate of a broken rod and the fish rate of a broken hook,
+// it is below the rate of any working item so wear matters for every tier
+const brokenRate = 35
+
+// itemDurability returns the max durability and the yen per point repair cost of an item
+func itemDurability(category string, id int) (int, int) {
+	switch category {
+	case "rod":
+		for _, i := range Items.Rod {
+			if i.ID == id {
+				return i.Durability, i.RepairCost
+			}
+		}
+	case "hook":
+		for _, i := range Items.Hook {
+			if i.ID == id {
+				return i.Durability, i.RepairCost
+			}
+		}
+	}
+	return 0, 0
+}
+
+func wearField(category string, id int) string {
+	return fmt.Sprintf("%s:%v", category, id)
+}
+
+func currentItem(inv UserItems, category string) int {
+	switch category {
+	case "rod":
+		return inv.Rod.Current
+	case "hook":
+		return inv.Hook.Current
+	}
+	return 0
+}
+
+// itemWear reads how many times an item has been used since it was last repaired, capped at its max durability
+func itemWear(c redis.Cmdable, userID, category string, id int) int {
+	limit, _ := itemDurability(category, id)
+	wear, _ := strconv.Atoi(c.HGet(DurabilityKey(userID), wearField(category, id)).Val())
+	if wear > limit {
+		return limit
+	}
+	return wear
+}
+
+// DBGetDurability returns the durability of a single owned item
+func DBGetDurability(userID, category string, id int) Durability {
+	limit, _ := itemDurability(category, id)
+	d := Durability{Item: id, Remaining: limit - itemWear(redisClient, userID, category, id), Max: limit}
+	d.Broken = limit > 0 && d.Remaining < 1
+	return d
+}
+
+// DBGetEquippedDurability returns the durability of every equipped item that can wear out
+func DBGetEquippedDurability(userID string) map[string]Durability {
+	inv := DBGetInventory(userID)
+	equipped := map[string]Durability{}
+	for _, c := range wearingItems {
+		equipped[c] = DBGetDurability(userID, c, currentItem(inv, c))
+	}
+	return equipped
+}
+
+// itemBroken reports whether an item has worn out and lost its effect
+func itemBroken(userID, category string, id int) bool {
+	return DBGetDurability(userID, category, id).Broken
+}
+
+// DBWearEquipment wears down a users equipped items after a cast
+func DBWearEquipment(userID string) {
+	_, err := redisClient.Pipelined(func(pipe redis.Pipeliner) error {
+		queueWear(pipe, userID, DBGetInventory(userID), 1)
+		return nil
+	})
+	if err != nil {
+		logError("Unable to wear equipment", err)
+	}
+}
+
+// queueWear wears down the equipped items of an inventory by a number of casts
+func queueWear(pipe redis.Pipeliner, userID string, inv UserItems, casts int) {
+	for _, c := range wearingItems {
+		id := currentItem(inv, c)
+		if limit, _ := itemDurability(c, id); limit > 0 {
+			pipe.HIncrBy(DurabilityKey(userID), wearField(c, id), int64(casts))
+		}
+	}
+}
+
+// DBRepairItem fully repairs a users equipped item of a category, paying for every point of wear from their wallet
+func DBRepairItem(userID, category string) (Durability, int, error) {
+	id := currentItem(DBGetInventory(userID), category)
+	limit, costPer := itemDurability(category, id)
+	if limit < 1 {
+		return Durability{}, 0, fmt.Errorf("Your %s does not wear out", category)
+	}
+	var cost int
+	err := watchTx(func(tx *redis.Tx) error {
+		wear := itemWear(tx, userID, category, id)
+		if wear == 0 {
+			return fmt.Errorf("Your %s does not need repairing", category)
+		}
+		cost = wear * costPer
+		if walletBalance(tx, userID) < cost {
+			return fmt.Errorf("Repairing your %s costs %v yen", category, cost)
+		}
+		_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.HDel(DurabilityKey(userID), wearField(category, id))
+			if cost > 0 {
+				pipe.IncrBy(WalletKey(userID), int64(-cost))
+			}
+			return nil
+		})
+		return err
+	}, DurabilityKey(userID), WalletKey(userID))
+	if err != nil {
+		return Durability{}, 0, err
+	}
+	return DBGetDurability(userID, category, id), cost, nil
+}
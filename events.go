@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var validLocations = map[string]bool{
+	"lake":  true,
+	"river": true,
+	"ocean": true,
+}
+
+// DBGetEvents returns every event created through the admin endpoint
+func DBGetEvents() ([]Event, error) {
+	var events []Event
+	data, err := redisClient.HGetAll(EventsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range data {
+		var ev Event
+		if err := json.Unmarshal([]byte(e), &ev); err != nil {
+			logError("Unable to unmarshal event", err)
+			continue
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// DBAddEvent stores a new event and returns it with its assigned id
+func DBAddEvent(ev Event) (Event, error) {
+	id, err := redisClient.Incr(EventIDKey).Result()
+	if err != nil {
+		return Event{}, err
+	}
+	ev.ID = strconv.FormatInt(id, 10)
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return Event{}, err
+	}
+	return ev, redisClient.HSet(EventsKey, ev.ID, data).Err()
+}
+
+// DBDeleteEvent removes an event created through the admin endpoint
+func DBDeleteEvent(eventID string) (bool, error) {
+	n, err := redisClient.HDel(EventsKey, eventID).Result()
+	return n > 0, err
+}
+
+// allEvents returns the events from events.json along with the ones created through the admin endpoint
+func allEvents() []Event {
+	events := append([]Event{}, Events.Events...)
+	db, err := DBGetEvents()
+	if err != nil {
+		logError("Unable to retrieve events", err)
+		return events
+	}
+	return append(events, db...)
+}
+
+// ActiveEvents returns the events currently running at a location, an empty location matches every event
+func ActiveEvents(location string) []Event {
+	var active []Event
+	for _, e := range allEvents() {
+		if e.active(CurrentTime) && e.appliesTo(location) {
+			active = append(active, e)
+		}
+	}
+	return active
+}
+
+// ListEventsAt returns the active and upcoming events sorted by start time
+func ListEventsAt(t time.Time) EventList {
+	list := EventList{[]Event{}, []Event{}}
+	for _, e := range allEvents() {
+		switch {
+		case e.active(t):
+			list.Active = append(list.Active, e)
+		case t.Before(e.Start):
+			list.Upcoming = append(list.Upcoming, e)
+		}
+	}
+	sort.Slice(list.Active, func(i, j int) bool { return list.Active[i].Start.Before(list.Active[j].Start) })
+	sort.Slice(list.Upcoming, func(i, j int) bool { return list.Upcoming[i].Start.Before(list.Upcoming[j].Start) })
+	return list
+}
+
+func (e Event) active(t time.Time) bool {
+	return !t.Before(e.Start) && t.Before(e.End)
+}
+
+func (e Event) appliesTo(location string) bool {
+	if len(e.Locations) == 0 || location == "" {
+		return true
+	}
+	for _, l := range e.Locations {
+		if l == location {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Event) validate() error {
+	if e.Name == "" {
+		return errors.New("Event name is required")
+	}
+	if !e.End.After(e.Start) {
+		return errors.New("Event must end after it starts")
+	}
+	if e.ExpMultiplier < 0 {
+		return errors.New("Exp multiplier cannot be negative")
+	}
+	for _, l := range e.Locations {
+		if !validLocations[l] {
+			return fmt.Errorf("Invalid location %s", l)
+		}
+	}
+	for _, g := range e.Buffs {
+		if err := g.validate(); err != nil {
+			return err
+		}
+	}
+	for _, f := range e.Fish {
+		if f.Tier < 1 || f.Tier > 5 {
+			return fmt.Errorf("Fish %s has an invalid tier", f.Name)
+		}
+		if len(f.Size) != 2 || f.Size[0] >= f.Size[1] {
+			return fmt.Errorf("Fish %s has an invalid size range", f.Name)
+		}
+	}
+	return nil
+}
+
+// eventFish returns the limited-time fish of a tier available at a location
+func eventFish(tier int, location string) []FishSpecies {
+	var fish []FishSpecies
+	for _, e := range ActiveEvents(location) {
+		for _, f := range e.Fish {
+			if f.Tier == tier {
+				fish = append(fish, f.FishSpecies)
+			}
+		}
+	}
+	return fish
+}
+
+// eventTrash returns the special trash available at a location
+func eventTrash(location string) []string {
+	var trash []string
+	for _, e := range ActiveEvents(location) {
+		trash = append(trash, e.Trash...)
+	}
+	return trash
+}
+
+// eventExpMultiplier returns the combined exp multiplier of every event active at a location
+func eventExpMultiplier(location string) float64 {
+	m := float64(1)
+	for _, e := range ActiveEvents(location) {
+		if e.ExpMultiplier > 0 {
+			m *= e.ExpMultiplier
+		}
+	}
+	return m
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var fishLocations = []string{"lake", "river", "ocean"}
+
+// locationTiers returns the fish tiers from fish.json for a location
+func locationTiers(location string) []FishTier {
+	switch location {
+	case "lake":
+		return Fish.Location.Lake
+	case "river":
+		return Fish.Location.River
+	}
+	return Fish.Location.Ocean
+}
+
+// DBRecordFishdex records a catch in a users fishdex and pays out any completion rewards it unlocked
+func DBRecordFishdex(userID string, f InvFish) {
+	key := FishdexKey(userID)
+	n, err := redisClient.HIncrBy(key, f.Name+":count", 1).Result()
+	if err != nil {
+		logError("Unable to increment fishdex count", err)
+		return
+	}
+	if _, err := hsetMax(key, f.Name+":largest", f.Size); err != nil {
+		logError("Unable to set fishdex largest size", err)
+	}
+	if n == 1 {
+		redisClient.HSetNX(key, f.Name+":first", CurrentTime.Unix())
+		dexUnlockRewards(userID)
+	}
+}
+
+// DBGetFishdex returns every fishdex entry of a user keyed by fish name
+func DBGetFishdex(userID string) map[string]FishdexEntry {
+	entries := map[string]FishdexEntry{}
+	data, err := redisClient.HGetAll(FishdexKey(userID)).Result()
+	if err != nil {
+		logError("Unable to retrieve fishdex", err)
+		return entries
+	}
+	for k, v := range data {
+		i := strings.LastIndex(k, ":")
+		if i < 0 {
+			continue
+		}
+		name := k[:i]
+		e := entries[name]
+		e.Name = name
+		switch k[i+1:] {
+		case "count":
+			e.Count, _ = strconv.Atoi(v)
+			e.Caught = e.Count > 0
+		case "largest":
+			e.Largest, _ = strconv.ParseFloat(v, 64)
+		case "first":
+			if u, err := strconv.ParseInt(v, 10, 64); err == nil {
+				t := time.Unix(u, 0).UTC()
+				e.FirstCaught = &t
+			}
+		}
+		entries[name] = e
+	}
+	return entries
+}
+
+// DBGetFishdexRewards returns the completion percentages a user has already been rewarded for
+func DBGetFishdexRewards(userID string) map[string]bool {
+	claimed := map[string]bool{}
+	for _, e := range redisClient.SMembers(FishdexRewKey(userID)).Val() {
+		claimed[e] = true
+	}
+	return claimed
+}
+
+// userFishdex builds a users fishdex for every fish in fish.json
+func userFishdex(userID string) FishdexData {
+	data := buildFishdex(DBGetFishdex(userID))
+	claimed := DBGetFishdexRewards(userID)
+	for _, rw := range Dex.Rewards {
+		rw.Unlocked = claimed[fmt.Sprintf("%v", rw.Completion)]
+		data.Rewards = append(data.Rewards, rw)
+	}
+	return data
+}
+
+func buildFishdex(entries map[string]FishdexEntry) FishdexData {
+	data := FishdexData{Locations: []FishdexLocation{}, Rewards: []FishdexReward{}}
+	seen := map[string]bool{}
+	caught := 0
+	for _, loc := range fishLocations {
+		l := FishdexLocation{Location: loc, Tiers: []FishdexTier{}}
+		var locTotal, locCaught int
+		for i, t := range locationTiers(loc) {
+			tier := FishdexTier{Tier: i + 1, Fish: []FishdexEntry{}}
+			tierCaught := 0
+			for _, f := range t.Fish {
+				e, ok := entries[f.Name]
+				if !ok {
+					e = FishdexEntry{Name: f.Name}
+				}
+				tier.Fish = append(tier.Fish, e)
+				if e.Caught {
+					tierCaught++
+				}
+				if !seen[f.Name] {
+					seen[f.Name] = true
+					if e.Caught {
+						caught++
+					}
+				}
+			}
+			tier.Completion = percent(tierCaught, len(t.Fish))
+			locTotal += len(t.Fish)
+			locCaught += tierCaught
+			l.Tiers = append(l.Tiers, tier)
+		}
+		l.Completion = percent(locCaught, locTotal)
+		data.Locations = append(data.Locations, l)
+	}
+	data.Completion = percent(caught, len(seen))
+	return data
+}
+
+// dexUnlockRewards pays out every fishdex reward a user has reached but not yet received
+func dexUnlockRewards(userID string) {
+	completion := buildFishdex(DBGetFishdex(userID)).Completion
+	for _, rw := range Dex.Rewards {
+		if completion < rw.Completion {
+			continue
+		}
+		n, err := redisClient.SAdd(FishdexRewKey(userID), fmt.Sprintf("%v", rw.Completion)).Result()
+		if err != nil {
+			logError("Unable to mark fishdex reward as unlocked", err)
+			continue
+		}
+		if n == 0 {
+			continue
+		}
+		if err := DBGiveReward(userID, rw.Reward); err != nil {
+			logError("Unable to give fishdex reward", err)
+		}
+	}
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Floor(float64(n)/float64(total)*10000) / 100
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis"
+)
+
+// giftLimit returns a configured daily gift limit, falling back to a default when it is not set
+func giftLimit(limit, def int) int {
+	if limit < 1 {
+		return def
+	}
+	return limit
+}
+
+func (g Gift) validate() error {
+	if g.From == g.To {
+		return errors.New("You cannot send a gift to yourself")
+	}
+	if g.Bait < 0 || g.Yen < 0 {
+		return errors.New("Gift amounts cannot be negative")
+	}
+	if g.Bait == 0 && g.Yen == 0 {
+		return errors.New("A gift needs bait or yen")
+	}
+	if g.Bait > 0 && (g.Tier < 1 || g.Tier > 5) {
+		return fmt.Errorf("Invalid bait tier %v", g.Tier)
+	}
+	return nil
+}
+
+// giftedToday returns how much bait and yen has been counted against a daily gift limit
+func giftedToday(c redis.Cmdable, key string) (int, int) {
+	data := c.HGetAll(key).Val()
+	bait, _ := strconv.Atoi(data["bait"])
+	yen, _ := strconv.Atoi(data["yen"])
+	return bait, yen
+}
+
+// DBSendGift moves bait or yen from one user to another within both of their daily limits
+// and records the gift for the recipient
+func DBSendGift(g Gift) (Gift, error) {
+	if err := g.validate(); err != nil {
+		return g, err
+	}
+	g.Sent = CurrentTime
+	day := questPeriodKey("daily", CurrentTime)
+	reset := questPeriodEnd("daily", CurrentTime)
+	sent, recv := GiftSentKey(g.From, day), GiftRecvKey(g.To, day)
+	capacity := DBGetBaitCapacity(g.To)
+	data, err := json.Marshal(g)
+	if err != nil {
+		return g, err
+	}
+	err = watchTx(func(tx *redis.Tx) error {
+		sentBait, sentYen := giftedToday(tx, sent)
+		recvBait, recvYen := giftedToday(tx, recv)
+		if sentBait+g.Bait > giftLimit(Gifts.SendBait, 25) || sentYen+g.Yen > giftLimit(Gifts.SendYen, 2500) {
+			return errors.New("You have reached your daily gift limit")
+		}
+		if recvBait+g.Bait > giftLimit(Gifts.ReceiveBait, 50) || recvYen+g.Yen > giftLimit(Gifts.ReceiveYen, 5000) {
+			return fmt.Errorf("%s cannot receive any more gifts today", DBGetTrackedUser(g.To))
+		}
+		if g.Bait > 0 {
+			if baitAmount(tx, g.From, g.Tier) < g.Bait {
+				return fmt.Errorf("You do not have %v tier %v bait", g.Bait, g.Tier)
+			}
+			if baitAmount(tx, g.To, g.Tier)+g.Bait > capacity {
+				return fmt.Errorf("%s does not have enough room for the tier %v bait", DBGetTrackedUser(g.To), g.Tier)
+			}
+		}
+		if walletBalance(tx, g.From) < g.Yen {
+			return fmt.Errorf("You do not have %v yen", g.Yen)
+		}
+		_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+			if g.Bait > 0 {
+				pipe.HIncrBy(BaitInvKey(g.From), strconv.Itoa(g.Tier), int64(-g.Bait))
+				pipe.HIncrBy(BaitInvKey(g.To), strconv.Itoa(g.Tier), int64(g.Bait))
+			}
+			if g.Yen > 0 {
+				pipe.IncrBy(WalletKey(g.From), int64(-g.Yen))
+				pipe.IncrBy(WalletKey(g.To), int64(g.Yen))
+			}
+			for _, key := range []string{sent, recv} {
+				pipe.HIncrBy(key, "bait", int64(g.Bait))
+				pipe.HIncrBy(key, "yen", int64(g.Yen))
+				pipe.ExpireAt(key, reset)
+			}
+			pipe.LPush(GiftsKey(g.To), data)
+			pipe.LTrim(GiftsKey(g.To), 0, 49)
+			return nil
+		})
+		return err
+	}, BaitInvKey(g.From), BaitInvKey(g.To), WalletKey(g.From), sent, recv)
+	return g, err
+}
+
+// DBGetGifts returns the most recent gifts a user has received
+func DBGetGifts(userID string) []Gift {
+	gifts := []Gift{}
+	for _, data := range redisClient.LRange(GiftsKey(userID), 0, -1).Val() {
+		var g Gift
+		if err := json.Unmarshal([]byte(data), &g); err != nil {
+			logError("Unable to unmarshal gift", err)
+			continue
+		}
+		gifts = append(gifts, g)
+	}
+	return gifts
+}
@@ -0,0 +1,87 @@
+package main
+
+import "testing"
+
+func TestSendGiftMovesBaitAndYen(t *testing.T) {
+	newTestRedis(t)
+	DBAddWallet("alice", 500)
+	redisClient.HSet(BaitInvKey("alice"), "3", 10)
+
+	if _, err := DBSendGift(Gift{From: "alice", To: "bob", Tier: 3, Bait: 4, Yen: 200}); err != nil {
+		t.Fatalf("sending gift: %v", err)
+	}
+	if got := DBGetWallet("alice"); got != 300 {
+		t.Errorf("sender wallet = %v, want 300", got)
+	}
+	if got := DBGetWallet("bob"); got != 200 {
+		t.Errorf("recipient wallet = %v, want 200", got)
+	}
+	if got := baitAmount(redisClient, "alice", 3); got != 6 {
+		t.Errorf("sender tier 3 bait = %v, want 6", got)
+	}
+	if got := baitAmount(redisClient, "bob", 3); got != 4 {
+		t.Errorf("recipient tier 3 bait = %v, want 4", got)
+	}
+	gifts := DBGetGifts("bob")
+	if len(gifts) != 1 || gifts[0].From != "alice" || gifts[0].Yen != 200 {
+		t.Errorf("recipient gifts = %+v, want the gift from alice", gifts)
+	}
+}
+
+func TestSendGiftFailsWithoutChanges(t *testing.T) {
+	newTestRedis(t)
+	DBAddWallet("alice", 100)
+	redisClient.HSet(BaitInvKey("alice"), "1", 10)
+	redisClient.HSet(BaitInvKey("bob"), "1", DBGetBaitCapacity("bob")-2)
+
+	cases := map[string]Gift{
+		"more yen than the sender has":   {From: "alice", To: "bob", Yen: 150},
+		"more bait than the sender has":  {From: "alice", To: "carol", Tier: 1, Bait: 11},
+		"more bait than the box can fit": {From: "alice", To: "bob", Tier: 1, Bait: 3},
+		"a gift to yourself":             {From: "alice", To: "alice", Yen: 10},
+	}
+	for name, g := range cases {
+		if _, err := DBSendGift(g); err == nil {
+			t.Errorf("sending %s succeeded", name)
+		}
+	}
+	if got := DBGetWallet("alice"); got != 100 {
+		t.Errorf("sender wallet = %v, want 100", got)
+	}
+	if got := baitAmount(redisClient, "alice", 1); got != 10 {
+		t.Errorf("sender tier 1 bait = %v, want 10", got)
+	}
+	if got := DBGetWallet("bob") + DBGetWallet("carol"); got != 0 {
+		t.Errorf("recipients received %v yen, want 0", got)
+	}
+	if got := len(DBGetGifts("bob")) + len(DBGetGifts("carol")); got != 0 {
+		t.Errorf("%v failed gifts were recorded", got)
+	}
+}
+
+func TestSendGiftDailyLimits(t *testing.T) {
+	newTestRedis(t)
+	defer func(g GiftConfig) { Gifts = g }(Gifts)
+	Gifts = GiftConfig{SendYen: 300, ReceiveYen: 400}
+	DBAddWallet("alice", 1000)
+	DBAddWallet("carol", 1000)
+
+	if _, err := DBSendGift(Gift{From: "alice", To: "bob", Yen: 250}); err != nil {
+		t.Fatalf("sending gift: %v", err)
+	}
+	if _, err := DBSendGift(Gift{From: "alice", To: "dave", Yen: 100}); err == nil {
+		t.Error("sender went over their daily limit")
+	}
+	if _, err := DBSendGift(Gift{From: "carol", To: "bob", Yen: 200}); err == nil {
+		t.Error("recipient went over their daily limit")
+	}
+	if _, err := DBSendGift(Gift{From: "carol", To: "bob", Yen: 150}); err != nil {
+		t.Errorf("sending gift within both limits: %v", err)
+	}
+	if got := DBGetWallet("bob"); got != 400 {
+		t.Errorf("recipient wallet = %v, want 400", got)
+	}
+	if got := DBGetWallet("alice"); got != 750 {
+		t.Errorf("sender wallet = %v, want 750", got)
+	}
+}
@@ -45,49 +45,208 @@ func Fishy(w http.ResponseWriter, r *http.Request) {
 	}
 	go CmdStats("fishy", msg.ID)
 	go DBTrackUser(msg.Author)
-	if DBCheckBlacklist(msg.Author.ID) {
+	c, ok := newCast(w, msg.Author, mux.Vars(r)["guildID"])
+	if !ok {
+		return
+	}
+	fc, e := fishCatch(c.bite, c.catch, c.fish)
+	c.begin()
+	c.finish(w, fc, e, selectTier(ExpToTier(DBGetGlobalScore(c.user.ID))))
+}
+
+// StartReel is the bite phase of a two-phase cast, it responds with a session the player has to react to
+func StartReel(w http.ResponseWriter, r *http.Request) {
+	var msg *discordgo.Message
+	defer r.Body.Close()
+	if err := readAndUnmarshal(r.Body, &msg); err != nil {
+		respondError(w, true,
+			fmt.Sprintf(
+				"Error reading and unmarshaling request\n%v",
+				err.Error(),
+			),
+		)
+		return
+	}
+	if err := reelConfigured(); err != nil {
+		respondError(w, true, err.Error())
+		return
+	}
+	go CmdStats("fishy", msg.ID)
+	go DBTrackUser(msg.Author)
+	c, ok := newCast(w, msg.Author, mux.Vars(r)["guildID"])
+	if !ok {
+		return
+	}
+	c.begin()
+	if !roll(c.bite) {
+		c.finish(w, false, "bite", 0)
+		return
+	}
+	tier := selectTier(ExpToTier(DBGetGlobalScore(c.user.ID)))
+	bite, err := DBStartReel(c, tier)
+	if err != nil {
+		logError("unable to start reel", err)
+		respondError(w, true, err.Error())
+		return
+	}
+	respond(w, bite)
+	log.WithFields(log.Fields{
+		"user":   c.user.ID,
+		"guild":  c.guildID,
+		"tier":   tier,
+		"window": bite.Window,
+	}).Debug("reel-bite")
+}
+
+// FinishReel is the catch phase of a two-phase cast, reacting in time with the right action
+// gives the player a catch roll the same as a normal cast
+func FinishReel(w http.ResponseWriter, r *http.Request) {
+	at := time.Now().UTC()
+	user := mux.Vars(r)["userID"]
+	var req ReelRequest
+	defer r.Body.Close()
+	if err := readAndUnmarshal(r.Body, &req); err != nil {
+		respondError(w, true,
+			fmt.Sprintf("Error unmarshaling request: %s", err.Error()),
+		)
+		return
+	}
+	if DBCheckBlacklist(user) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	s, err := DBTakeReel(user, req.Token)
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	c := s.cast()
+	if !s.reeled(req.Action, at) || !roll(c.catch) {
+		c.finish(w, false, "catch", 0)
+		return
+	}
+	e := "garbage"
+	if roll(c.fish) {
+		e = "fish"
+	}
+	c.finish(w, true, e, s.Tier)
+}
+
+// BatchFish makes several casts in one request for users that own an auto-fisher
+func BatchFish(w http.ResponseWriter, r *http.Request) {
+	var req BatchRequest
+	defer r.Body.Close()
+	if err := readAndUnmarshal(r.Body, &req); err != nil {
+		respondError(w, true,
+			fmt.Sprintf(
+				"Error reading and unmarshaling request\n%v",
+				err.Error(),
+			),
+		)
+		return
+	}
+	if req.Message == nil || req.Message.Author == nil {
+		respondError(w, true, "Request is missing a message")
+		return
+	}
+	go CmdStats("fishy:batch", req.Message.ID)
+	go DBTrackUser(req.Message.Author)
+	limit := DBGetAutoFisherCasts(req.Message.Author.ID)
+	if limit < 1 {
+		respondError(w, false, "You need an auto-fisher to cast in batches")
+		return
+	}
+	if req.Casts < 1 || req.Casts > limit {
+		respondError(w, false, fmt.Sprintf("Your auto-fisher can make between 1 and %v casts at a time", limit))
+		return
+	}
+	c, ok := newCast(w, req.Message.Author, mux.Vars(r)["guildID"])
+	if !ok {
+		return
+	}
+	res, err := DBBatchCast(c, req.Casts)
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	res.Notified = c.shareBatch(res)
+	c.recordBatch(res)
+	respond(w, res)
+	log.WithFields(log.Fields{
+		"user":    c.user.ID,
+		"guild":   c.guildID,
+		"casts":   res.Casts,
+		"fish":    res.Fish,
+		"garbage": res.Garbage,
+		"fails":   res.Fails,
+		"stopped": res.Stopped,
+		"rates": map[string]interface{}{
+			"bite":  c.bite,
+			"catch": c.catch,
+			"fish":  c.fish,
+		},
+	}).Debug("batch-cast")
+}
+
+// cast stores the state of a single cast from its rates until its outcome is resolved
+type cast struct {
+	user    *discordgo.User
+	guildID string
+	loc     string
+	density UserLocDensity
+	bite    int64
+	catch   int64
+	fish    int64
+	pity    int64
+	party   string
+	buffs   buffSet
+}
+
+// checkCast makes sure a user is able to cast, responding with the reason if they can't
+func checkCast(w http.ResponseWriter, user *discordgo.User) bool {
+	if DBCheckBlacklist(user.ID) {
 		respondError(w, false,
 			fmt.Sprintf(
 				":x: | User %v#%v has been blacklisted from fishing.",
-				msg.Author.Username,
-				msg.Author.Discriminator,
+				user.Username,
+				user.Discriminator,
 			),
 		)
-		return
+		return false
 	}
-	if gathering, timeLeft := DBCheckGatherBait(msg.Author.ID); gathering {
+	if gathering, timeLeft := DBCheckGatherBait(user.ID); gathering {
 		respondError(w, false,
 			fmt.Sprintf(
 				":x: | You are currently gathering bait. Please wait %v for you to finish.",
 				timeLeft.String(),
 			),
 		)
-		return
+		return false
 	}
-	if rl, timeLeft := DBCheckRateLimit("fishy", msg.Author.ID); rl {
+	if rl, timeLeft := DBCheckRateLimit("fishy", user.ID); rl {
 		respondError(w, false,
 			fmt.Sprintf(
 				"Please wait %v before fishing again!",
 				timeLeft.String(),
 			),
 		)
-		return
+		return false
 	}
-	fmt.Println(msg.Author.Username)
-	noinv := DBCheckMissingInventory(msg.Author.ID)
+	fmt.Println(user.Username)
+	noinv := DBCheckMissingInventory(user.ID)
 	if len(noinv) > 0 {
 		sort.Strings(noinv)
 
 		if i := sort.SearchStrings(noinv, "rod"); i < len(noinv) && noinv[i] == "rod" {
-			DBIncInvEE(msg.Author.ID)
-			a := DBGetInvEE(msg.Author.ID)
+			DBIncInvEE(user.ID)
+			a := DBGetInvEE(user.ID)
 			num := math.Floor(float64(a / 10))
 			respondError(w, false, Secrets.InvEE[int(num)])
 			if num == float64(len(Secrets.InvEE))-1 {
-				DBEditItemTier(msg.Author.ID, "rod", "1")
-				DBEditItemTier(msg.Author.ID, "hook", "1")
+				DBEditItemTier(user.ID, "rod", "1")
+				DBEditItemTier(user.ID, "hook", "1")
 			}
-			return
+			return false
 		}
 		if i := sort.SearchStrings(noinv, "hook"); i < len(noinv) && noinv[i] == "hook" {
 			respondError(w, false,
@@ -96,7 +255,7 @@ func Fishy(w http.ResponseWriter, r *http.Request) {
 						"*Something inside of you thinks that fish won't bite without a hook...*",
 				),
 			)
-			return
+			return false
 		}
 		respondError(w, false,
 			fmt.Sprintf(
@@ -105,95 +264,153 @@ func Fishy(w http.ResponseWriter, r *http.Request) {
 				strings.Join(noinv, ", "),
 			),
 		)
-		return
+		return false
 	}
 
-	if amt, err := DBGetCurrentBaitAmt(msg.Author.ID); err != nil {
+	if amt, err := DBGetCurrentBaitAmt(user.ID); err != nil {
 		respondError(w, true,
 			fmt.Sprintf("There was an error"),
 		)
 		logError("Error converting current bait tier", err)
-		return
+		return false
 	} else {
 		if amt < 1 {
 			respondError(w, false,
 				fmt.Sprintf("You do not own any bait of your currently equipped tier. Please buy more bait or switch tiers."),
 			)
-			return
+			return false
 		}
 	}
 
-	loc := DBGetLocation(msg.Author.ID)
-	density, _ := DBGetLocDensity(msg.Author.ID)
-	bite := DBGetBiteRate(msg.Author.ID, density, loc)
-	catch, err := DBGetCatchRate(msg.Author.ID)
-	if err != nil {
+	return true
+}
+
+// newCast checks a user is able to cast and looks up their rates, responding with the reason if they can't
+func newCast(w http.ResponseWriter, user *discordgo.User, guildID string) (cast, bool) {
+	if !checkCast(w, user) {
+		return cast{}, false
+	}
+	c := cast{user: user, guildID: guildID, loc: DBGetLocation(user.ID)}
+	c.density, _ = DBGetLocDensity(user.ID)
+	c.bite = DBGetBiteRate(user.ID, c.density, c.loc)
+	var err error
+	if c.catch, err = DBGetCatchRate(user.ID); err != nil {
 		respondError(w, true, err.Error())
-		return
+		return cast{}, false
 	}
-	fish, err := DBGetFishRate(msg.Author.ID)
-	if err != nil {
+	if c.fish, err = DBGetFishRate(user.ID); err != nil {
 		respondError(w, true, err.Error())
-		return
+		return cast{}, false
+	}
+	DBGetBuffs(user.ID).apply(&c)
+	c.pity = DBGetLuck(user.ID).pity()
+	c.bite += c.pity
+	c.catch += c.pity
+	if p, ok := castParty(user.ID, guildID); ok {
+		c.party = p.ID
+		c.bite += p.bonus()
 	}
+	perks := DBGetClubPerks(guildID)
+	c.bite += perks.Bite
+	c.catch += perks.Catch
+	return c, true
+}
+
+// cooldown returns how long a user has to wait to fish again for every cast they make in a batch, cooldown buffs shorten it
+func (c cast) cooldown() time.Duration {
+	return time.Duration(float64(FishyTimeout) * c.buffs.cooldown())
+}
+
+// begin records that a cast was made
+func (c cast) begin() {
+	go DBAddCast(c.user.ID, c.guildID)
+	go DBWearEquipment(c.user.ID)
+	emitActivity(Activity{Type: "cast", UserID: c.user.ID, GuildID: c.guildID, Location: c.loc})
+}
 
-	fc, e := fishCatch(bite, catch, fish)
-	go DBAddCast(msg.Author.ID, mux.Vars(r)["guildID"])
+// finish applies the outcome of a cast and responds with it, a caught fish is of the given tier
+func (c cast) finish(w http.ResponseWriter, fc bool, e string, tier int) {
+	outcome := "fail"
+	if fc {
+		outcome = e
+	}
+	luck, err := DBRecordLuck(c.user.ID, outcome)
+	if err != nil {
+		logError("Unable to record luck", err)
+	}
 	if fc {
 		if e == "garbage" {
-			go DBAddFishToInv(msg.Author.ID, "garbage", 5)
-			go DBAddGarbage(msg.Author.ID, mux.Vars(r)["guildID"])
-			respond(w, makeEmbedTrash(msg.Author.Username, loc, randomTrash(), density))
+			go DBAddFishToInv(c.user.ID, "garbage", garbageWorth)
+			go DBAddGarbage(c.user.ID, c.guildID)
+			emitActivity(Activity{Type: "garbage", UserID: c.user.ID, GuildID: c.guildID, Location: c.loc})
+			respond(w, addLuckFields(makeEmbedTrash(c.user.Username, c.loc, randomTrash(c.loc), c.density), luck))
 			log.WithFields(log.Fields{
-				"user":     msg.Author.ID,
-				"guild":    mux.Vars(r)["guildID"],
-				"location": loc,
+				"user":     c.user.ID,
+				"guild":    c.guildID,
+				"location": c.loc,
 				"rates": map[string]interface{}{
-					"bite":  bite,
-					"catch": catch,
-					"fish":  fish,
+					"bite":  c.bite,
+					"catch": c.catch,
+					"fish":  c.fish,
 				},
-				"density": density,
+				"density": c.density,
 			}).Debug("garbage-catch")
 		}
 		if e == "fish" {
-			level := ExpToTier(DBGetGlobalScore(msg.Author.ID))
-			f := getFish(level, loc)
-			go DBIncrAvgFishStats(msg.Author.ID, mux.Vars(r)["guildID"], f.Size)
-			err := DBAddFishToInv(msg.Author.ID, "fish", f.Price)
+			f := fishOfTier(tier, c.loc)
+			go DBIncrAvgFishStats(c.user.ID, c.guildID, f.Size)
+			err := DBAddFishToInv(c.user.ID, "fish", f.Price)
 			if err != nil {
 				respondError(w, false, "Your fish inventory is full and you cannot carry any more. You are forced to throw the fish back.")
 			} else {
-				go DBGiveGlobalScore(msg.Author.ID, 1)
-				go DBLoseBait(msg.Author.ID)
-				newDen, _ := DBGetSetLocDensity(loc, msg.Author.ID)
-				respond(w, makeEmbedFish(f, msg.Author.Username, newDen))
+				exp := eventExpMultiplier(c.loc) * c.buffs.multiplier("exp") * prestigeExpMultiplier(c.user.ID) *
+					luck.streakMultiplier() * DBGetClubPerks(c.guildID).multiplier()
+				go DBGiveGlobalScore(c.user.ID, exp)
+				go DBGiveGuildScore(c.user.ID, exp, c.guildID)
+				if f, err = DBStoreFish(c.user.ID, f); err != nil {
+					logError("Unable to store fish", err)
+				}
+				go DBRecordFishdex(c.user.ID, f)
+				emitActivity(Activity{Type: "fish", UserID: c.user.ID, GuildID: c.guildID, Location: c.loc, Fish: f})
+				go DBLoseBait(c.user.ID)
+				records, err := DBUpdateRecords(c.user.ID, c.guildID, f)
+				if err != nil {
+					logError("Unable to update records", err)
+				}
+				notified := DBShareCatch(c.party, c.user.ID, f)
+				newDen, _ := DBGetSetLocDensity(c.loc, c.user.ID)
+				respond(w, addPartyField(addLuckFields(addRecordFields(makeEmbedFish(f, c.user.Username, newDen), records), luck), c.user.Username, notified))
 				log.WithFields(log.Fields{
-					"user":     msg.Author.ID,
-					"guild":    mux.Vars(r)["guildID"],
+					"user":     c.user.ID,
+					"guild":    c.guildID,
 					"fish-len": f.Size,
 					"price":    f.Price,
 					"tier":     f.Tier,
+					"records":  records,
+					"luck":     luck,
+					"party":    c.party,
 					"rates": map[string]interface{}{
-						"bite":  bite,
-						"catch": catch,
-						"fish":  fish,
+						"bite":  c.bite,
+						"catch": c.catch,
+						"fish":  c.fish,
 					},
-					"density": density,
+					"density": c.density,
 				}).Debug("fish-catch")
 			}
 		}
 	} else {
-		respond(w, makeEmbedFail(msg.Author.Username, loc, failed(e, msg.Author.ID), density))
+		respond(w, addLuckFields(makeEmbedFail(c.user.Username, c.loc, failed(e, c.user.ID), c.density), luck))
+		emitActivity(Activity{Type: "fail", UserID: c.user.ID, GuildID: c.guildID, Location: c.loc})
 		log.WithFields(log.Fields{
-			"user":  msg.Author.ID,
-			"guild": mux.Vars(r)["guildID"],
+			"user":  c.user.ID,
+			"guild": c.guildID,
 			"rates": map[string]interface{}{
-				"bite":  bite,
-				"catch": catch,
-				"fish":  fish,
+				"bite":  c.bite,
+				"catch": c.catch,
+				"fish":  c.fish,
 			},
-			"density": density,
+			"density": c.density,
+			"luck":    luck,
 		}).Debug("fail-catch")
 	}
 }
@@ -238,6 +455,25 @@ func makeEmbedFish(fish InvFish, user string, locDen UserLocDensity) *discordgo.
 	}
 }
 
+func addRecordFields(embed *discordgo.MessageEmbed, records RecordFlags) *discordgo.MessageEmbed {
+	var broken []string
+	if records.Global {
+		broken = append(broken, ":earth_americas: Global")
+	}
+	if records.Guild {
+		broken = append(broken, ":cityscape: Guild")
+	}
+	if records.Personal {
+		broken = append(broken, ":bust_in_silhouette: Personal")
+	}
+	if len(broken) > 0 {
+		embed.Fields = append(embed.Fields,
+			&discordgo.MessageEmbedField{Name: "New record!", Value: strings.Join(broken, "\n"), Inline: false},
+		)
+	}
+	return embed
+}
+
 func tierToEmbedColor(tier int) int {
 	switch tier {
 	case 1:
@@ -261,11 +497,16 @@ func Inventory(w http.ResponseWriter, r *http.Request) {
 
 	respond(w,
 		map[string]interface{}{
-			"items":    DBGetInventory(user),
-			"fish":     DBGetFishInv(user),
-			"maxFish":  DBGetInvCapacity(user),
-			"maxBait":  DBGetBaitCapacity(user),
-			"userTier": ExpToTier(DBGetGlobalScore(user)),
+			"items":       DBGetInventory(user),
+			"fish":        DBGetFishInv(user),
+			"maxFish":     DBGetInvCapacity(user),
+			"maxBait":     DBGetBaitCapacity(user),
+			"maxAquarium": DBGetAquariumCapacity(user),
+			"maxBatch":    DBGetAutoFisherCasts(user),
+			"userTier":    ExpToTier(DBGetGlobalScore(user)),
+			"yen":         DBGetWallet(user),
+			"consumables": DBGetConsumables(user),
+			"durability":  DBGetEquippedDurability(user),
 		},
 	)
 }
@@ -401,6 +642,7 @@ func BuyItem(w http.ResponseWriter, r *http.Request) {
 			DBGetInventory(user),
 		},
 	)
+	emitActivity(Activity{Type: "purchase", UserID: user, Item: item.Category})
 	log.WithFields(log.Fields{
 		"user":     user,
 		"category": item.Category,
@@ -408,6 +650,33 @@ func BuyItem(w http.ResponseWriter, r *http.Request) {
 	}).Debug("item-bought")
 }
 
+// EquipItem switches a users current rod, hook, vehicle or bait box to another owned item
+func EquipItem(w http.ResponseWriter, r *http.Request) {
+	var req EquipRequest
+	defer r.Body.Close()
+	if err := readAndUnmarshal(r.Body, &req); err != nil {
+		respondError(w, true,
+			fmt.Sprintf("Error unmarshaling request: %s", err.Error()),
+		)
+		return
+	}
+	user := mux.Vars(r)["userID"]
+	if DBCheckBlacklist(user) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	if err := DBEquipItem(user, req.Category, req.Item); err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, DBGetInventory(user))
+	log.WithFields(log.Fields{
+		"user":     user,
+		"category": req.Category,
+		"item":     req.Item,
+	}).Debug("item-equipped")
+}
+
 // Blacklist blacklists a user from using fishy
 func Blacklist(w http.ResponseWriter, r *http.Request) {
 	DBBlackListUser(mux.Vars(r)["userID"])
@@ -422,8 +691,8 @@ func Unblacklist(w http.ResponseWriter, r *http.Request) {
 
 // StartGatherBait starts the timeout for gathering bait
 func StartGatherBait(w http.ResponseWriter, r *http.Request) {
-	DBStartGatherBait(mux.Vars(r)["userID"])
-	fmt.Fprint(w, ":ok_hand: you decide to spend the next 6 hours filling up your bait box with bait")
+	timeout, _ := DBStartGatherBait(mux.Vars(r)["userID"])
+	fmt.Fprintf(w, ":ok_hand: you decide to spend the next %v filling up your bait box with bait", timeout)
 	log.WithFields(log.Fields{
 		"user": mux.Vars(r)["userID"],
 	}).Debug("user-gather-bait")
@@ -439,6 +708,8 @@ func GetLeaderboard(w http.ResponseWriter, r *http.Request) {
 	var data LeaderboardRequest
 	var s []redis.Z
 	var scores []LeaderboardUser
+	var rank int64
+	var score float64
 	var err error
 	if err := readAndUnmarshal(r.Body, &data); err != nil {
 		respondError(w, true,
@@ -449,7 +720,61 @@ func GetLeaderboard(w http.ResponseWriter, r *http.Request) {
 		)
 		return
 	}
-	if data.Global {
+	if data.Board != "" && data.Board != "exp" && data.Period != "" && data.Period != "all" {
+		respondError(w, false, fmt.Sprintf("The %s leaderboard cannot be limited to a period", data.Board))
+		return
+	}
+	var label string
+	if data.Board == "prestige" {
+		label = "Prestige"
+		key, err := DBPrestigeBoard(data.Global, data.GuildID)
+		if err == nil {
+			s, err = DBGetBoardPage(key, data.Page)
+		}
+		if err != nil {
+			respondError(w, true,
+				fmt.Sprintf(
+					"Could not retrieve scores: %v",
+					err.Error(),
+				),
+			)
+			return
+		}
+		rank, score = DBGetBoardRank(key, data.User)
+	} else if data.Board != "" && data.Board != "exp" {
+		var key string
+		key, label, err = DBMetricBoard(data.Board, data.Species, data.Global, data.GuildID)
+		if err == nil {
+			s, err = DBGetBoardPage(key, data.Page)
+		}
+		if err != nil {
+			respondError(w, true,
+				fmt.Sprintf(
+					"Could not retrieve scores: %v",
+					err.Error(),
+				),
+			)
+			return
+		}
+		rank, score = DBGetBoardRank(key, data.User)
+	} else if data.Period != "" && data.Period != "all" {
+		var key string
+		key, label, err = DBScoreWindowBoard(data.Global, data.GuildID, data.Period, data.Previous)
+		if err == nil {
+			s, err = DBGetBoardPage(key, data.Page)
+		}
+		if err != nil {
+			respondError(w, true,
+				fmt.Sprintf(
+					"Could not retrieve scores: %v",
+					err.Error(),
+				),
+			)
+			return
+		}
+		rank, score = DBGetBoardRank(key, data.User)
+	} else if data.Global {
+		rank, score = DBGetGlobalScoreRank(data.User)
 		s, err = DBGetGlobalScorePage(data.Page)
 		if err != nil {
 			respondError(w, true,
@@ -461,6 +786,7 @@ func GetLeaderboard(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	} else {
+		rank, score = DBGetGuildScoreRank(data.User, data.GuildID)
 		s, err = DBGetGuildScorePage(data.GuildID, data.Page)
 		if err != nil {
 			respondError(w, true,
@@ -476,7 +802,7 @@ func GetLeaderboard(w http.ResponseWriter, r *http.Request) {
 		scores = append(scores, LeaderboardUser{e.Score, e.Member})
 	}
 
-	l, err := LeaderboardTemp(scores, data.Global, data.User, data.GuildID, data.GuildName)
+	l, err := LeaderboardTemp(scores, data.Global, rank, score, label, data.GuildName)
 	if err != nil {
 		respondError(w, true,
 			fmt.Sprintf(
@@ -512,7 +838,7 @@ func CheckTime(w http.ResponseWriter, r *http.Request) {
 
 //
 func RandTrash(w http.ResponseWriter, r *http.Request) {
-	respond(w, "you caught "+randomTrash())
+	respond(w, "you caught "+randomTrash(""))
 }
 
 //
@@ -611,16 +937,18 @@ func EquippedBaitPost(w http.ResponseWriter, r *http.Request) {
 //
 func SellFish(w http.ResponseWriter, r *http.Request) {
 	user := mux.Vars(r)["userID"]
-	worth := DBSellFish(user)
+	worth, yen := DBSellFish(user)
+	emitActivity(Activity{Type: "sell", UserID: user, Amount: yen})
 	respond(w,
 		fmt.Sprintf(
-			"You redeemed %s fish, %s legendaries, and %s garbage for %s :yen:",
-			worth["fish"], worth["legendaries"], worth["garbage"], worth["worth"],
+			"You redeemed %s fish, %s legendaries, and %s garbage for %v :yen:, your wallet now holds %v :yen:",
+			worth["fish"], worth["legendaries"], worth["garbage"], yen, DBGetWallet(user),
 		),
 	)
 	log.WithFields(log.Fields{
 		"user":        user,
-		"worth":       worth["worth"],
+		"yen":         yen,
+		"base-worth":  worth["worth"],
 		"fish":        worth["fish"],
 		"legendaries": worth["legendaries"],
 		"garbage":     worth["garbage"],
@@ -645,91 +973,862 @@ func Stats(w http.ResponseWriter, r *http.Request) {
 	}).Debug("user-stats")
 }
 
-func respond(w http.ResponseWriter, data interface{}) {
-	e := json.NewEncoder(w)
-	e.SetEscapeHTML(false)
-	e.Encode(
-		APIResponse{
-			false,
-			"",
-			data,
-		},
-	)
-}
-
-func respondError(w http.ResponseWriter, isErr bool, err string) {
-	json.NewEncoder(w).Encode(
-		APIResponse{
-			isErr,
-			err,
-			"",
-		},
-	)
+// ListEvents lists the active and upcoming events
+func ListEvents(w http.ResponseWriter, r *http.Request) {
+	respond(w, ListEventsAt(CurrentTime))
 }
 
-func fishCatch(bite, catch, fish int64) (bool, string) {
-	var r1, r2, r3 int64
-	if r, err := rand.Int(rand.Reader, big.NewInt(99)); err == nil {
-		r1 = r.Int64()
+// CreateEvent creates a new event
+func CreateEvent(w http.ResponseWriter, r *http.Request) {
+	var ev Event
+	defer r.Body.Close()
+	if err := readAndUnmarshal(r.Body, &ev); err != nil {
+		respondError(w, true,
+			fmt.Sprintf("Error unmarshaling request: %s", err.Error()),
+		)
+		return
 	}
-	if r, err := rand.Int(rand.Reader, big.NewInt(99)); err == nil {
-		r2 = r.Int64()
+	if err := ev.validate(); err != nil {
+		respondError(w, true, err.Error())
+		return
 	}
-	if r, err := rand.Int(rand.Reader, big.NewInt(99)); err == nil {
-		r3 = r.Int64()
+	ev, err := DBAddEvent(ev)
+	if err != nil {
+		logError("unable to add event", err)
+		respondError(w, true,
+			fmt.Sprintf("Error adding event: %s", err.Error()),
+		)
+		return
 	}
-	// fmt.Println(r1, bite)
-	// fmt.Println(r2, catch)
-	// fmt.Println(r3, fish)
+	respond(w, ev)
+	log.WithFields(log.Fields{
+		"event": ev.ID,
+		"name":  ev.Name,
+		"start": ev.Start,
+		"end":   ev.End,
+	}).Debug("event-created")
+}
 
-	if r1 <= bite {
-		if r2 <= catch {
-			if r3 <= fish {
-				return true, "fish"
-			}
-			return true, "garbage"
-		}
-		return false, "catch"
+// DeleteEvent deletes an event created through CreateEvent
+func DeleteEvent(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["eventID"]
+	ok, err := DBDeleteEvent(id)
+	if err != nil {
+		logError("unable to delete event", err)
+		respondError(w, true,
+			fmt.Sprintf("Error deleting event: %s", err.Error()),
+		)
+		return
 	}
-	return false, "bite"
+	if !ok {
+		respondError(w, false, fmt.Sprintf("Event %s does not exist", id))
+		return
+	}
+	respond(w, fmt.Sprintf("Successfully deleted event %s", id))
 }
 
-func readAndUnmarshal(data io.Reader, fmt interface{}) error {
-	body, err := ioutil.ReadAll(data)
+// Fishdex returns a users caught and uncaught fish along with their completion
+func Fishdex(w http.ResponseWriter, r *http.Request) {
+	respond(w, userFishdex(mux.Vars(r)["userID"]))
+}
+
+// GlobalRecords returns the global record holders of a species
+func GlobalRecords(w http.ResponseWriter, r *http.Request) {
+	species := mux.Vars(r)["species"]
+	board, err := DBGetRecordBoard(RecordGlobKey(species), species)
 	if err != nil {
-		return err
+		respondError(w, true,
+			fmt.Sprintf("Could not retrieve records: %v", err.Error()),
+		)
+		return
 	}
-	err = json.Unmarshal(body, &fmt)
+	respond(w, board)
+}
+
+// GuildRecords returns the guild record holders of a species
+func GuildRecords(w http.ResponseWriter, r *http.Request) {
+	species := mux.Vars(r)["species"]
+	board, err := DBGetRecordBoard(RecordGuildKey(mux.Vars(r)["guildID"], species), species)
 	if err != nil {
-		return err
+		respondError(w, true,
+			fmt.Sprintf("Could not retrieve records: %v", err.Error()),
+		)
+		return
 	}
-	return nil
+	respond(w, board)
 }
 
-func failed(e, uID string) string {
-	if e == "catch" {
-		go DBLoseBait(uID)
-		return "a fish bit but you were unable to wrangle it in"
-	}
-	if e == "bite" {
-		return "you couldn't get a fish to bite"
-	}
-	return ""
+// UserRecords returns a users personal records
+func UserRecords(w http.ResponseWriter, r *http.Request) {
+	respond(w, DBGetUserRecords(mux.Vars(r)["userID"]))
 }
 
-func randomTrash() string {
-	if r, err := rand.Int(rand.Reader, big.NewInt(int64(len(Trash.Regular.Text)-1))); err != nil {
-		logError("unable to generate random number", err)
-		return "hehexd this didnt work - " + err.Error()
-	} else {
-		return Trash.Regular.Text[int(r.Int64())]
+// Achievements returns a users progress towards every achievement
+func Achievements(w http.ResponseWriter, r *http.Request) {
+	respond(w, DBGetAchievements(mux.Vars(r)["userID"]))
+}
+
+// GetQuests returns a users daily and weekly quests
+func GetQuests(w http.ResponseWriter, r *http.Request) {
+	user := mux.Vars(r)["userID"]
+	quests := map[string]QuestBoardStatus{}
+	for _, period := range questPeriods {
+		status, err := DBGetQuestStatus(user, period)
+		if err != nil {
+			logError("unable to retrieve quests", err)
+			respondError(w, true,
+				fmt.Sprintf("Could not retrieve quests: %v", err.Error()),
+			)
+			return
+		}
+		quests[period] = status
 	}
+	respond(w, quests)
 }
 
-var t1 = 50
-var t2 = 29
-var t3 = 15
-var t4 = 5
+// ClaimQuest pays out the reward of a completed quest
+func ClaimQuest(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if DBCheckBlacklist(vars["userID"]) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	q, err := DBClaimQuest(vars["userID"], vars["questID"])
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, q)
+	log.WithFields(log.Fields{
+		"user":  vars["userID"],
+		"quest": q.ID,
+	}).Debug("quest-claimed")
+}
+
+// RerollQuest swaps a quest for a different one
+func RerollQuest(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	q, err := DBRerollQuest(vars["userID"], vars["questID"])
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, q)
+	log.WithFields(log.Fields{
+		"user":      vars["userID"],
+		"old-quest": vars["questID"],
+		"new-quest": q.ID,
+	}).Debug("quest-rerolled")
+}
+
+// DailyGet returns a users daily reward streak
+func DailyGet(w http.ResponseWriter, r *http.Request) {
+	respond(w, DBGetDaily(mux.Vars(r)["userID"]))
+}
+
+// DailyClaim claims a users daily reward
+func DailyClaim(w http.ResponseWriter, r *http.Request) {
+	user := mux.Vars(r)["userID"]
+	if DBCheckBlacklist(user) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	status, wait, err := DBClaimDaily(user)
+	if err != nil {
+		logError("unable to claim daily reward", err)
+		respondError(w, true,
+			fmt.Sprintf("Error claiming daily reward: %s", err.Error()),
+		)
+		return
+	}
+	if wait > 0 {
+		respondError(w, false,
+			fmt.Sprintf(
+				"You have already claimed your daily reward. Please wait %v to claim it again.",
+				wait.String(),
+			),
+		)
+		return
+	}
+	respond(w, status)
+	log.WithFields(log.Fields{
+		"user":   user,
+		"streak": status.Streak,
+	}).Debug("daily-claimed")
+}
+
+// CreateTournament creates a new tournament for a guild
+func CreateTournament(w http.ResponseWriter, r *http.Request) {
+	var t Tournament
+	defer r.Body.Close()
+	if err := readAndUnmarshal(r.Body, &t); err != nil {
+		respondError(w, true,
+			fmt.Sprintf("Error unmarshaling request: %s", err.Error()),
+		)
+		return
+	}
+	t.GuildID = mux.Vars(r)["guildID"]
+	if t.Start.IsZero() {
+		t.Start = CurrentTime
+	}
+	if t.Duration > 0 {
+		t.End = t.Start.Add(time.Duration(t.Duration) * time.Minute)
+	}
+	if err := t.validate(); err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	t, err := DBCreateTournament(t)
+	if err != nil {
+		logError("unable to create tournament", err)
+		respondError(w, true,
+			fmt.Sprintf("Error creating tournament: %s", err.Error()),
+		)
+		return
+	}
+	respond(w, t)
+	log.WithFields(log.Fields{
+		"tournament": t.ID,
+		"guild":      t.GuildID,
+		"scoring":    t.Scoring,
+		"end":        t.End,
+	}).Debug("tournament-created")
+}
+
+// ListTournaments lists the running and upcoming tournaments of a guild
+func ListTournaments(w http.ResponseWriter, r *http.Request) {
+	respond(w, DBGetGuildTournaments(mux.Vars(r)["guildID"]))
+}
+
+// TournamentArchive lists the results of a guilds finished tournaments
+func TournamentArchive(w http.ResponseWriter, r *http.Request) {
+	respond(w, DBGetTournamentArchive(mux.Vars(r)["guildID"]))
+}
+
+// TournamentStandings returns the live standings of a tournament, or its results once it has ended
+func TournamentStandings(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["tournamentID"]
+	if res, err := DBGetTournamentResults(id); err == nil && res.Tournament.GuildID == mux.Vars(r)["guildID"] {
+		respond(w, res)
+		return
+	}
+	t, err := DBGetTournament(id)
+	if err != nil || t.GuildID != mux.Vars(r)["guildID"] {
+		respondError(w, false, fmt.Sprintf("Tournament %s does not exist", id))
+		return
+	}
+	standings, err := DBGetStandings(t)
+	if err != nil {
+		respondError(w, true,
+			fmt.Sprintf("Could not retrieve standings: %v", err.Error()),
+		)
+		return
+	}
+	respond(w, TournamentResults{t, false, standings})
+}
+
+// StoredFish lists every individual fish in a users inventory
+func StoredFish(w http.ResponseWriter, r *http.Request) {
+	respond(w, DBGetStoredFish(mux.Vars(r)["userID"]))
+}
+
+// CreateTrade proposes a trade to another user
+func CreateTrade(w http.ResponseWriter, r *http.Request) {
+	var t TradeOffer
+	defer r.Body.Close()
+	if err := readAndUnmarshal(r.Body, &t); err != nil {
+		respondError(w, true,
+			fmt.Sprintf("Error unmarshaling request: %s", err.Error()),
+		)
+		return
+	}
+	t.From = mux.Vars(r)["userID"]
+	if err := t.validate(); err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	if DBCheckBlacklist(t.From) || DBCheckBlacklist(t.To) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	t, err := DBCreateTrade(t)
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, t)
+	log.WithFields(log.Fields{
+		"trade": t.ID,
+		"from":  t.From,
+		"to":    t.To,
+	}).Debug("trade-created")
+}
+
+// GetTrades lists a users pending trades and trade history
+func GetTrades(w http.ResponseWriter, r *http.Request) {
+	respond(w, DBGetTrades(mux.Vars(r)["userID"]))
+}
+
+// AcceptTrade accepts a trade and exchanges everything in it
+func AcceptTrade(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	t, err := DBGetTrade(vars["tradeID"])
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	if DBCheckBlacklist(t.From) || DBCheckBlacklist(t.To) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	t, err = DBAcceptTrade(vars["userID"], vars["tradeID"])
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, t)
+	log.WithFields(log.Fields{
+		"trade": t.ID,
+		"from":  t.From,
+		"to":    t.To,
+	}).Debug("trade-accepted")
+}
+
+// DeclineTrade declines a trade, or cancels it when used by the user who proposed it
+func DeclineTrade(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	user := vars["userID"]
+	t, err := DBCloseTrade(vars["tradeID"], func(t TradeOffer) (string, error) {
+		switch user {
+		case t.To:
+			return "declined", nil
+		case t.From:
+			return "cancelled", nil
+		}
+		return "", errors.New("You are not part of this trade")
+	})
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, t)
+	log.WithFields(log.Fields{
+		"trade":  t.ID,
+		"user":   user,
+		"status": t.Status,
+	}).Debug("trade-closed")
+}
+
+// GetMarket lists the live sell price of every species
+func GetMarket(w http.ResponseWriter, r *http.Request) {
+	respond(w, MarketPrices())
+}
+
+// CreateAuction lists a fish on the auction house
+func CreateAuction(w http.ResponseWriter, r *http.Request) {
+	var a Auction
+	defer r.Body.Close()
+	if err := readAndUnmarshal(r.Body, &a); err != nil {
+		respondError(w, true,
+			fmt.Sprintf("Error unmarshaling request: %s", err.Error()),
+		)
+		return
+	}
+	a.Seller = mux.Vars(r)["userID"]
+	a.Created = CurrentTime
+	a.Ends = a.Created.Add(time.Duration(a.Duration) * time.Minute)
+	if err := a.validate(); err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	if DBCheckBlacklist(a.Seller) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	a, err := DBCreateAuction(a)
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, a)
+	log.WithFields(log.Fields{
+		"auction": a.ID,
+		"seller":  a.Seller,
+		"fish":    a.Fish.Name,
+		"reserve": a.Reserve,
+	}).Debug("auction-created")
+}
+
+// ListAuctions lists every open auction
+func ListAuctions(w http.ResponseWriter, r *http.Request) {
+	respond(w, DBGetAuctions())
+}
+
+// UserAuctions lists the auctions a user has recently listed or bid on
+func UserAuctions(w http.ResponseWriter, r *http.Request) {
+	respond(w, DBGetUserAuctions(mux.Vars(r)["userID"]))
+}
+
+// BidAuction places a bid on an auction
+func BidAuction(w http.ResponseWriter, r *http.Request) {
+	var b AuctionBid
+	defer r.Body.Close()
+	if err := readAndUnmarshal(r.Body, &b); err != nil {
+		respondError(w, true,
+			fmt.Sprintf("Error unmarshaling request: %s", err.Error()),
+		)
+		return
+	}
+	vars := mux.Vars(r)
+	if DBCheckBlacklist(vars["userID"]) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	a, err := DBBidAuction(vars["userID"], vars["auctionID"], b.Amount)
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, a)
+	log.WithFields(log.Fields{
+		"auction": a.ID,
+		"bidder":  a.HighBidder,
+		"bid":     a.HighBid,
+	}).Debug("auction-bid")
+}
+
+// GetAquarium returns the contents of a users aquarium
+func GetAquarium(w http.ResponseWriter, r *http.Request) {
+	respond(w, DBGetAquarium(mux.Vars(r)["userID"]))
+}
+
+// MoveAquarium moves fish between a users inventory and their aquarium
+func MoveAquarium(w http.ResponseWriter, r *http.Request) {
+	var m AquariumMove
+	defer r.Body.Close()
+	if err := readAndUnmarshal(r.Body, &m); err != nil {
+		respondError(w, true,
+			fmt.Sprintf("Error unmarshaling request: %s", err.Error()),
+		)
+		return
+	}
+	vars := mux.Vars(r)
+	user := vars["userID"]
+	if err := m.validate(); err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	if DBCheckBlacklist(user) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	move := DBShowFish
+	if vars["action"] == "remove" {
+		move = DBUnshowFish
+	}
+	if err := move(user, m.Fish); err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, DBGetAquarium(user))
+	log.WithFields(log.Fields{
+		"user":   user,
+		"action": vars["action"],
+		"fish":   len(m.Fish),
+	}).Debug("aquarium-moved")
+}
+
+// AquariumLeaderboard returns the most valuable aquariums of a guild
+func AquariumLeaderboard(w http.ResponseWriter, r *http.Request) {
+	board, err := DBGetAquariumBoard(mux.Vars(r)["guildID"])
+	if err != nil {
+		logError("unable to retrieve aquarium leaderboard", err)
+		respondError(w, true,
+			fmt.Sprintf("Error retrieving aquarium leaderboard: %s", err.Error()),
+		)
+		return
+	}
+	respond(w, board)
+}
+
+// GetRecipes lists every recipe and the consumables they make
+func GetRecipes(w http.ResponseWriter, r *http.Request) {
+	respond(w, Recipes)
+}
+
+// Craft makes a recipe from a users fish and garbage
+func Craft(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	user := vars["userID"]
+	if DBCheckBlacklist(user) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	res, err := DBCraft(user, vars["recipeID"])
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, res)
+	emitActivity(Activity{Type: "craft", UserID: user, Item: res.Recipe.ID})
+	log.WithFields(log.Fields{
+		"user":   user,
+		"recipe": res.Recipe.ID,
+		"fish":   len(res.Used),
+	}).Debug("item-crafted")
+}
+
+// GetBuffs lists a users active buffs with their remaining time
+func GetBuffs(w http.ResponseWriter, r *http.Request) {
+	respond(w, DBGetBuffs(mux.Vars(r)["userID"]))
+}
+
+// UseConsumable uses one of a users consumables and applies its buffs
+func UseConsumable(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	user := vars["userID"]
+	if DBCheckBlacklist(user) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	c, err := DBUseConsumable(user, vars["consumableID"])
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, DBGetBuffs(user))
+	log.WithFields(log.Fields{
+		"user":       user,
+		"consumable": c.ID,
+	}).Debug("consumable-used")
+}
+
+// RepairItem repairs a users equipped rod or hook
+func RepairItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	user := vars["userID"]
+	if DBCheckBlacklist(user) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	d, cost, err := DBRepairItem(user, vars["category"])
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, map[string]interface{}{
+		"durability": d,
+		"cost":       cost,
+	})
+	log.WithFields(log.Fields{
+		"user":     user,
+		"category": vars["category"],
+		"item":     d.Item,
+		"cost":     cost,
+	}).Debug("item-repaired")
+}
+
+// GetPrestige returns a users prestige level, bonuses and history
+func GetPrestige(w http.ResponseWriter, r *http.Request) {
+	respond(w, DBGetPrestige(mux.Vars(r)["userID"]))
+}
+
+// DoPrestige resets a users progress in exchange for a prestige level
+func DoPrestige(w http.ResponseWriter, r *http.Request) {
+	user := mux.Vars(r)["userID"]
+	if DBCheckBlacklist(user) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	rec, err := DBPrestige(user)
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, DBGetPrestige(user))
+	log.WithFields(log.Fields{
+		"user":  user,
+		"level": rec.Level,
+		"exp":   rec.Exp,
+	}).Debug("user-prestiged")
+}
+
+// GetNet returns a users deployed net and what it is expected to have caught so far
+func GetNet(w http.ResponseWriter, r *http.Request) {
+	y, err := DBGetNetYield(mux.Vars(r)["userID"])
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, y)
+}
+
+// DeployNet deploys a net for a user at a location
+func DeployNet(w http.ResponseWriter, r *http.Request) {
+	var req NetRequest
+	defer r.Body.Close()
+	if err := readAndUnmarshal(r.Body, &req); err != nil {
+		respondError(w, true,
+			fmt.Sprintf("Error unmarshaling request: %s", err.Error()),
+		)
+		return
+	}
+	user := mux.Vars(r)["userID"]
+	if DBCheckBlacklist(user) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	n, err := DBDeployNet(user, req)
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, n)
+	log.WithFields(log.Fields{
+		"user":     user,
+		"guild":    n.GuildID,
+		"location": n.Location,
+		"ends":     n.Ends,
+	}).Debug("net-deployed")
+}
+
+// CollectNet pulls in a users net and adds its catches to their inventory
+func CollectNet(w http.ResponseWriter, r *http.Request) {
+	user := mux.Vars(r)["userID"]
+	if DBCheckBlacklist(user) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	res, err := DBCollectNet(user)
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, res)
+	log.WithFields(log.Fields{
+		"user":    user,
+		"casts":   res.Casts,
+		"fish":    res.Fish,
+		"garbage": res.Garbage,
+	}).Debug("net-collected")
+}
+
+// GetParty returns a users party with its bonus and recent rare catches
+func GetParty(w http.ResponseWriter, r *http.Request) {
+	p, err := DBGetParty(mux.Vars(r)["userID"])
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, p)
+}
+
+// CreateParty starts a new party led by a user
+func CreateParty(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	user := vars["userID"]
+	if DBCheckBlacklist(user) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	p, err := DBCreateParty(vars["guildID"], user)
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, p)
+	log.WithFields(log.Fields{
+		"user":  user,
+		"guild": p.GuildID,
+		"party": p.ID,
+	}).Debug("party-created")
+}
+
+// JoinParty adds a user to a party from their guild
+func JoinParty(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	user := vars["userID"]
+	if DBCheckBlacklist(user) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	p, err := DBJoinParty(vars["guildID"], user, vars["partyID"])
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, p)
+	log.WithFields(log.Fields{
+		"user":    user,
+		"guild":   p.GuildID,
+		"party":   p.ID,
+		"members": len(p.Members),
+	}).Debug("party-joined")
+}
+
+// LeaveParty removes a user from their party
+func LeaveParty(w http.ResponseWriter, r *http.Request) {
+	user := mux.Vars(r)["userID"]
+	if err := DBLeaveParty(user); err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, "You left your party")
+	log.WithFields(log.Fields{
+		"user": user,
+	}).Debug("party-left")
+}
+
+// GetGifts lists the most recent gifts a user has received
+func GetGifts(w http.ResponseWriter, r *http.Request) {
+	respond(w, DBGetGifts(mux.Vars(r)["userID"]))
+}
+
+// SendGift sends bait or yen from one user to another
+func SendGift(w http.ResponseWriter, r *http.Request) {
+	var g Gift
+	defer r.Body.Close()
+	if err := readAndUnmarshal(r.Body, &g); err != nil {
+		respondError(w, true,
+			fmt.Sprintf("Error unmarshaling request: %s", err.Error()),
+		)
+		return
+	}
+	vars := mux.Vars(r)
+	g.From, g.To = vars["userID"], vars["recipientID"]
+	if DBCheckBlacklist(g.From) || DBCheckBlacklist(g.To) {
+		respondError(w, false, "User blacklisted")
+		return
+	}
+	g, err := DBSendGift(g)
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, g)
+	log.WithFields(log.Fields{
+		"from": g.From,
+		"to":   g.To,
+		"tier": g.Tier,
+		"bait": g.Bait,
+		"yen":  g.Yen,
+	}).Debug("gift-sent")
+}
+
+// GetClub returns the profile of a guilds fishing club
+func GetClub(w http.ResponseWriter, r *http.Request) {
+	respond(w, DBGetClub(mux.Vars(r)["guildID"]))
+}
+
+// EditClub changes the name and description of a guilds fishing club
+func EditClub(w http.ResponseWriter, r *http.Request) {
+	var req ClubRequest
+	defer r.Body.Close()
+	if err := readAndUnmarshal(r.Body, &req); err != nil {
+		respondError(w, true,
+			fmt.Sprintf("Error unmarshaling request: %s", err.Error()),
+		)
+		return
+	}
+	guild := mux.Vars(r)["guildID"]
+	c, err := DBEditClub(guild, req)
+	if err != nil {
+		respondError(w, false, err.Error())
+		return
+	}
+	respond(w, c)
+	log.WithFields(log.Fields{
+		"guild": guild,
+		"name":  c.Name,
+	}).Debug("club-edited")
+}
+
+func respond(w http.ResponseWriter, data interface{}) {
+	e := json.NewEncoder(w)
+	e.SetEscapeHTML(false)
+	e.Encode(
+		APIResponse{
+			false,
+			"",
+			data,
+		},
+	)
+}
+
+func respondError(w http.ResponseWriter, isErr bool, err string) {
+	json.NewEncoder(w).Encode(
+		APIResponse{
+			isErr,
+			err,
+			"",
+		},
+	)
+}
+
+func fishCatch(bite, catch, fish int64) (bool, string) {
+	var r1, r2, r3 int64
+	if r, err := rand.Int(rand.Reader, big.NewInt(99)); err == nil {
+		r1 = r.Int64()
+	}
+	if r, err := rand.Int(rand.Reader, big.NewInt(99)); err == nil {
+		r2 = r.Int64()
+	}
+	if r, err := rand.Int(rand.Reader, big.NewInt(99)); err == nil {
+		r3 = r.Int64()
+	}
+	// fmt.Println(r1, bite)
+	// fmt.Println(r2, catch)
+	// fmt.Println(r3, fish)
+
+	if r1 <= bite {
+		if r2 <= catch {
+			if r3 <= fish {
+				return true, "fish"
+			}
+			return true, "garbage"
+		}
+		return false, "catch"
+	}
+	return false, "bite"
+}
+
+func readAndUnmarshal(data io.Reader, fmt interface{}) error {
+	body, err := ioutil.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(body, &fmt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func failed(e, uID string) string {
+	if e == "catch" {
+		go DBLoseBait(uID)
+		return "a fish bit but you were unable to wrangle it in"
+	}
+	if e == "bite" {
+		return "you couldn't get a fish to bite"
+	}
+	return ""
+}
+
+func randomTrash(location string) string {
+	trash := append(append([]string{}, Trash.Regular.Text...), eventTrash(location)...)
+	if r, err := rand.Int(rand.Reader, big.NewInt(int64(len(trash)))); err != nil {
+		logError("unable to generate random number", err)
+		return "hehexd this didnt work - " + err.Error()
+	} else {
+		return trash[int(r.Int64())]
+	}
+}
+
+var t1 = 50
+var t2 = 29
+var t3 = 15
+var t4 = 5
 var t5 = 1
 var t1Total = t1
 var t2Total = t1Total + t2
@@ -738,15 +1837,13 @@ var t4Total = t3Total + t4
 var t5Total = t4Total + t5
 
 func getFish(tier int, location string) InvFish {
-	_tier := selectTier(tier)
-	base := Fish.Location.Ocean
-	switch location {
-	case "lake":
-		base = Fish.Location.Lake
-	case "river":
-		base = Fish.Location.River
-	}
-	fish := base[_tier-1].Fish
+	return fishOfTier(selectTier(tier), location)
+}
+
+// fishOfTier returns a random fish of a tier from a location
+func fishOfTier(_tier int, location string) InvFish {
+	base := locationTiers(location)
+	fish := append(append([]FishSpecies{}, base[_tier-1].Fish...), eventFish(_tier, location)...)
 	var rand1, rand2 int64
 	// fish number
 	if r, err := rand.Int(rand.Reader, big.NewInt(int64(len(fish)-1))); err == nil {
@@ -769,7 +1866,7 @@ func getFish(tier int, location string) InvFish {
 			"price": sellPrice,
 		},
 	}).Debug("rand-fish")
-	return InvFish{location, _fish.Name, sellPrice, r, _tier, _fish.Pun, _fish.Image}
+	return InvFish{"", location, _fish.Name, sellPrice, r, _tier, _fish.Pun, _fish.Image}
 }
 
 func getFishPrice(tier int, min, max, l float64) float64 {
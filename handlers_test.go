@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-redis/redis"
+)
+
+// getTestLeaderboard requests a leaderboard from the handler and decodes its response
+func getTestLeaderboard(t *testing.T, req LeaderboardRequest) APIResponse {
+	t.Helper()
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	GetLeaderboard(w, httptest.NewRequest("POST", "/v1/leaderboard", bytes.NewReader(body)))
+	var res APIResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	return res
+}
+
+func TestGetLeaderboardBoardsAndPeriods(t *testing.T) {
+	newTestRedis(t)
+	redisClient.ZAdd(ScoreGlobalKey, redis.Z{Score: 500, Member: "alice"})
+	redisClient.Pipelined(func(pipe redis.Pipeliner) error {
+		queueScoreWindows(pipe, "", "alice", 40)
+		return nil
+	})
+	incrMetric("alice", "", "casts", 12)
+
+	cases := []struct {
+		name string
+		req  LeaderboardRequest
+		want string
+	}{
+		{"all time exp", LeaderboardRequest{}, "Total Points: 500"},
+		{"all time exp by name", LeaderboardRequest{Board: "exp", Period: "all"}, "Total Points: 500"},
+		{"weekly exp", LeaderboardRequest{Period: "weekly"}, "Weekly Points: 40"},
+		{"daily exp by name", LeaderboardRequest{Board: "exp", Period: "daily"}, "Daily Points: 40"},
+		{"previous monthly exp", LeaderboardRequest{Period: "monthly", Previous: true}, "Previous Monthly Points"},
+		{"casts", LeaderboardRequest{Board: "casts"}, "Casts: 12"},
+		{"all time casts", LeaderboardRequest{Board: "casts", Period: "all"}, "Casts: 12"},
+	}
+	for _, c := range cases {
+		c.req.Global = true
+		c.req.Page = 1
+		c.req.User = "alice"
+		res := getTestLeaderboard(t, c.req)
+		if res.Error || res.Message != "" {
+			t.Errorf("%s: error %q", c.name, res.Message)
+			continue
+		}
+		if data, _ := res.Data.(string); !strings.Contains(data, c.want) {
+			t.Errorf("%s: leaderboard %q does not contain %q", c.name, data, c.want)
+		}
+	}
+}
+
+func TestGetLeaderboardRejectsInvalidRequests(t *testing.T) {
+	newTestRedis(t)
+
+	cases := []struct {
+		name    string
+		req     LeaderboardRequest
+		isErr   bool
+		message string
+	}{
+		{"casts limited to a period", LeaderboardRequest{Board: "casts", Period: "weekly"}, false, "The casts leaderboard cannot be limited to a period"},
+		{"prestige limited to a period", LeaderboardRequest{Board: "prestige", Period: "daily"}, false, "The prestige leaderboard cannot be limited to a period"},
+		{"unknown period", LeaderboardRequest{Period: "hourly"}, true, "Invalid leaderboard period hourly"},
+		{"unknown board", LeaderboardRequest{Board: "shoes"}, true, "Invalid leaderboard shoes"},
+		{"species without a species", LeaderboardRequest{Board: "species"}, true, "Species leaderboards need a species"},
+	}
+	for _, c := range cases {
+		c.req.Global = true
+		c.req.Page = 1
+		c.req.User = "alice"
+		res := getTestLeaderboard(t, c.req)
+		if res.Error != c.isErr || !strings.Contains(res.Message, c.message) {
+			t.Errorf("%s: got error %v %q, want %v %q", c.name, res.Error, res.Message, c.isErr, c.message)
+		}
+		if data, _ := res.Data.(string); data != "" {
+			t.Errorf("%s: responded with a leaderboard %q", c.name, data)
+		}
+	}
+}
@@ -7,6 +7,10 @@ import (
 
 var (
 	ScoreGuildKey  = func(guildID string) string { return "exp:guild:" + guildID }
+	ScoreWindowKey = func(window string) string { return "exp:global:" + window }
+	GuildWindowKey = func(guildID, window string) string { return "exp:guild:" + guildID + ":" + window }
+	MetricKey      = func(metric string) string { return "board:" + metric }
+	GuildMetricKey = func(guildID, metric string) string { return "board:guild:" + guildID + ":" + metric }
 	LocDensityKey  = func(userID string) string { return "user:locationdensity:" + userID }
 	LocationKey    = func(userID string) string { return "user:location:" + userID }
 	InventoryKey   = func(userID string) string { return "user:inventory:" + userID }
@@ -25,6 +29,47 @@ var (
 	HourlyCmdTrack = func(cmd string) string { return "tracking:hourly:" + cmd }
 	DailyCmdTrack  = func(cmd string) string { return "tracking:daily:" + cmd }
 	TotalCmdTrack  = func(cmd string) string { return "tracking:total:" + cmd }
+	WalletKey      = func(userID string) string { return "wallet:" + userID }
+	FishdexKey     = func(userID string) string { return "fishdex:" + userID }
+	FishdexRewKey  = func(userID string) string { return "fishdex:rewards:" + userID }
+	RecordGlobKey  = func(species string) string { return "records:global:" + species }
+	RecordGuildKey = func(guildID, species string) string { return "records:guild:" + guildID + ":" + species }
+	AchievedKey    = func(userID string) string { return "achievements:" + userID }
+	AchieveProgKey = func(userID string) string { return "achievements:progress:" + userID }
+	QuestKey       = func(userID, period string) string { return "quests:" + userID + ":" + period }
+	QuestProgKey   = func(userID, period string) string { return "quests:progress:" + userID + ":" + period }
+	QuestClaimKey  = func(userID, period string) string { return "quests:claimed:" + userID + ":" + period }
+	DailyKey       = func(userID string) string { return "daily:" + userID }
+	GuildTourneys  = func(guildID string) string { return "tournaments:guild:" + guildID }
+	TourneyArchKey = func(guildID string) string { return "tournaments:archive:" + guildID }
+	StandingsKey   = func(tournamentID string) string { return "tournament:standings:" + tournamentID }
+	TourneyResKey  = func(tournamentID string) string { return "tournament:results:" + tournamentID }
+	CatchesKey     = func(userID string) string { return "fish:catches:" + userID }
+	TradeKey       = func(tradeID string) string { return "trade:" + tradeID }
+	TradesInKey    = func(userID string) string { return "trades:incoming:" + userID }
+	TradesOutKey   = func(userID string) string { return "trades:outgoing:" + userID }
+	TradeHistKey   = func(userID string) string { return "trades:history:" + userID }
+	AuctionKey     = func(auctionID string) string { return "auction:" + auctionID }
+	AuctionsKey    = func(userID string) string { return "auctions:user:" + userID }
+	AquariumKey    = func(userID string) string { return "aquarium:" + userID }
+	AquariumTopKey = func(guildID string) string { return "aquarium:top:" + guildID }
+	ConsumableKey  = func(userID string) string { return "consumables:" + userID }
+	BuffKey        = func(userID, kind string) string { return "buff:" + kind + ":" + userID }
+	DurabilityKey  = func(userID string) string { return "durability:" + userID }
+	PrestigeHist   = func(userID string) string { return "prestige:history:" + userID }
+	PrestigeTopKey = func(guildID string) string { return "prestige:top:" + guildID }
+	ReelKey        = func(userID string) string { return "reel:" + userID }
+	LuckKey        = func(userID string) string { return "luck:" + userID }
+	NetKey         = func(userID string) string { return "net:" + userID }
+	PartyKey       = func(partyID string) string { return "party:" + partyID }
+	PartyUserKey   = func(userID string) string { return "party:user:" + userID }
+	PartyFeedKey   = func(partyID string) string { return "party:catches:" + partyID }
+	GiftsKey       = func(userID string) string { return "gifts:" + userID }
+	GiftSentKey    = func(userID, day string) string { return "gifts:sent:" + day + ":" + userID }
+	GiftRecvKey    = func(userID, day string) string { return "gifts:received:" + day + ":" + userID }
+	ClubKey        = func(guildID string) string { return "club:" + guildID }
+	ClubWeekKey    = func(guildID, week string) string { return "club:week:" + week + ":" + guildID }
+	ClubGoalKey    = func(guildID, week string) string { return "club:goals:" + week + ":" + guildID }
 	Morning1       = time.Date(0, 0, 0, 9, 0, 0, 0, time.UTC)
 	Morning2       = time.Date(0, 0, 0, 15, 59, 59, 999, time.UTC)
 	Night1         = time.Date(0, 0, 0, 16, 0, 0, 0, time.UTC)
@@ -36,4 +81,23 @@ const (
 	FishyTimeout      = 10 * time.Second
 	GatherBaitTimeout = 6 * time.Hour
 	ScoreGlobalKey    = "exp:global"
+	EventsKey         = "events"
+	EventIDKey        = "events:id"
+	TournamentsKey    = "tournaments"
+	TourneyIDKey      = "tournaments:id"
+	TourneyEndKey     = "tournaments:ending"
+	FishIDKey         = "fish:id"
+	TradeIDKey        = "trades:id"
+	TradeExpireKey    = "trades:expiring"
+	TradeTimeout      = 24 * time.Hour
+	MarketKey         = "market"
+	MarketPrevKey     = "market:previous"
+	MarketTickKey     = "market:tick"
+	AuctionIDKey      = "auctions:id"
+	AuctionEndKey     = "auctions:ending"
+	AuctionMinLength  = 10 * time.Minute
+	AuctionMaxLength  = 7 * 24 * time.Hour
+	AquariumValueKey  = "aquarium:value"
+	PrestigeKey       = "prestige"
+	PartyIDKey        = "party:id"
 )
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis"
+	"github.com/iopred/discordgo"
+)
+
+// luckScript records the outcome of a cast, failures build up pity until something is caught
+// and fish caught in a row build up a streak until anything else happens
+var luckScript = redis.NewScript(`
+if ARGV[1] == "fail" then
+	redis.call("HINCRBY", KEYS[1], "fails", 1)
+	redis.call("HSET", KEYS[1], "streak", 0)
+else
+	redis.call("HSET", KEYS[1], "fails", 0)
+	if ARGV[1] == "fish" then
+		redis.call("HINCRBY", KEYS[1], "streak", 1)
+	else
+		redis.call("HSET", KEYS[1], "streak", 0)
+	end
+end
+return {tonumber(redis.call("HGET", KEYS[1], "fails")), tonumber(redis.call("HGET", KEYS[1], "streak"))}
+`)
+
+// DBGetLuck returns a users current run of failed casts and catch streak
+func DBGetLuck(userID string) Luck {
+	return luckOf(redisClient, userID)
+}
+
+func luckOf(c redis.Cmdable, userID string) Luck {
+	data := c.HGetAll(LuckKey(userID)).Val()
+	fails, _ := strconv.Atoi(data["fails"])
+	streak, _ := strconv.Atoi(data["streak"])
+	return Luck{fails, streak}
+}
+
+// DBRecordLuck records whether a cast caught a fish, garbage or failed and returns the users new luck
+func DBRecordLuck(userID, outcome string) (Luck, error) {
+	res, err := luckScript.Run(redisClient, []string{LuckKey(userID)}, outcome).Result()
+	if err != nil {
+		return Luck{}, err
+	}
+	vals, ok := res.([]interface{})
+	if !ok || len(vals) != 2 {
+		return Luck{}, fmt.Errorf("unexpected luck result %v", res)
+	}
+	return Luck{int(vals[0].(int64)), int(vals[1].(int64))}, nil
+}
+
+// record applies the outcome of a cast the same way luckScript does
+func (l Luck) record(outcome string) Luck {
+	switch outcome {
+	case "fish":
+		return Luck{0, l.Streak + 1}
+	case "garbage":
+		return Luck{}
+	}
+	return Luck{l.Fails + 1, 0}
+}
+
+// pity returns how much is added to the bite and catch rates after a run of failed casts
+func (l Luck) pity() int64 {
+	p := int64(l.Fails) * LuckConf.PityStep
+	if p > LuckConf.PityMax {
+		return LuckConf.PityMax
+	}
+	return p
+}
+
+// streakMultiplier returns the exp multiplier of a catch streak, the first fish of a streak gets no bonus
+func (l Luck) streakMultiplier() float64 {
+	if l.Streak < 2 {
+		return 1
+	}
+	b := float64(l.Streak-1) * LuckConf.StreakExp
+	if b > LuckConf.StreakMax {
+		b = LuckConf.StreakMax
+	}
+	return 1 + b
+}
+
+func addLuckFields(embed *discordgo.MessageEmbed, l Luck) *discordgo.MessageEmbed {
+	if p := l.pity(); p > 0 {
+		embed.Fields = append(embed.Fields,
+			&discordgo.MessageEmbedField{Name: "Bad luck protection", Value: fmt.Sprintf("+%v%% bite and catch rate on your next cast", p), Inline: false},
+		)
+	}
+	if m := l.streakMultiplier(); m > 1 {
+		embed.Fields = append(embed.Fields,
+			&discordgo.MessageEmbedField{Name: "Catch streak", Value: fmt.Sprintf("%v in a row, +%.0f%% exp", l.Streak, (m-1)*100), Inline: false},
+		)
+	}
+	return embed
+}
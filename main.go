@@ -10,8 +10,10 @@ import (
 
 func main() {
 	logrus.Info("dean") // never remove this line
+	setupDatabase()
 	router := NewRouter()
 	t := time.Tick(10 * time.Second)
+	go dispatchActivities()
 
 	go func() {
 		for {
@@ -20,5 +22,23 @@ func main() {
 		}
 	}()
 
+	m := time.Tick(1 * time.Minute)
+
+	go func() {
+		for {
+			<-m
+			endTournaments()
+			expireTrades()
+			settleAuctions()
+		}
+	}()
+
+	go func() {
+		for {
+			time.Sleep(marketInterval())
+			driftMarket()
+		}
+	}()
+
 	log.Fatal(http.ListenAndServe(":8080", router))
 }
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"math"
+	pRand "math/rand"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// marketShiftScript moves a species multiplier by a delta while keeping it within the market bounds
+var marketShiftScript = redis.NewScript(`
+local cur = tonumber(redis.call("HGET", KEYS[1], ARGV[1])) or 1
+local val = math.max(tonumber(ARGV[3]), math.min(tonumber(ARGV[4]), cur + tonumber(ARGV[2])))
+redis.call("HSET", KEYS[1], ARGV[1], val)
+return tostring(val)
+`)
+
+func marketInterval() time.Duration {
+	if Market.IntervalMinutes < 1 {
+		return 10 * time.Minute
+	}
+	return time.Duration(Market.IntervalMinutes) * time.Minute
+}
+
+func marketBounds() (float64, float64) {
+	if Market.Max <= Market.Min {
+		return 0.5, 1.5
+	}
+	return Market.Min, Market.Max
+}
+
+// marketSpecies returns every species in fish.json with its tier
+func marketSpecies() []MarketPrice {
+	var species []MarketPrice
+	seen := map[string]bool{}
+	for _, loc := range fishLocations {
+		for i, t := range locationTiers(loc) {
+			for _, f := range t.Fish {
+				if seen[f.Name] {
+					continue
+				}
+				seen[f.Name] = true
+				species = append(species, MarketPrice{Species: f.Name, Tier: i + 1})
+			}
+		}
+	}
+	return species
+}
+
+// getMultipliers reads the multipliers stored in a key with the given client so they can be read inside transactions
+func getMultipliers(c redis.Cmdable, key string) map[string]float64 {
+	m := map[string]float64{}
+	for k, v := range c.HGetAll(key).Val() {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			logError("Unable to parse market multiplier", err)
+			continue
+		}
+		m[k] = f
+	}
+	return m
+}
+
+// DBGetMarket returns the current multiplier of every species that has moved from its base price
+func DBGetMarket() map[string]float64 {
+	return getMultipliers(redisClient, MarketKey)
+}
+
+func multiplier(m map[string]float64, species string) float64 {
+	if v, ok := m[species]; ok {
+		return v
+	}
+	return 1
+}
+
+// DBShiftMarket moves a species multiplier by a delta
+func DBShiftMarket(species string, delta float64) error {
+	lo, hi := marketBounds()
+	return marketShiftScript.Run(redisClient, []string{MarketKey}, species, delta, lo, hi).Err()
+}
+
+// driftMarket snapshots every multiplier for trends, then moves each one back
+// towards its base price with some random drift
+func driftMarket() {
+	// only one instance moves the market each interval
+	if !redisClient.SetNX(MarketTickKey, "", marketInterval()/2).Val() {
+		return
+	}
+	cur := DBGetMarket()
+	prev := map[string]interface{}{}
+	for _, p := range marketSpecies() {
+		m := multiplier(cur, p.Species)
+		prev[p.Species] = m
+		delta := (1-m)*Market.Recovery + (pRand.Float64()*2-1)*Market.Drift
+		if err := DBShiftMarket(p.Species, delta); err != nil {
+			logError("Unable to shift market multiplier", err)
+		}
+	}
+	if len(prev) > 0 {
+		redisClient.HMSet(MarketPrevKey, prev)
+	}
+}
+
+// MarketPrices returns the live price range and trend of every species
+func MarketPrices() []MarketPrice {
+	cur := DBGetMarket()
+	prev := getMultipliers(redisClient, MarketPrevKey)
+	prices := []MarketPrice{}
+	for _, p := range marketSpecies() {
+		p.Multiplier = multiplier(cur, p.Species)
+		if p.Tier <= len(Fish.Prices) && len(Fish.Prices[p.Tier-1]) == 2 {
+			p.MinPrice = math.Floor(Fish.Prices[p.Tier-1][0] * p.Multiplier)
+			p.MaxPrice = math.Floor(Fish.Prices[p.Tier-1][1] * p.Multiplier)
+		}
+		switch diff := p.Multiplier - multiplier(prev, p.Species); {
+		case diff > 0.001:
+			p.Trend = "up"
+		case diff < -0.001:
+			p.Trend = "down"
+		default:
+			p.Trend = "steady"
+		}
+		prices = append(prices, p)
+	}
+	return prices
+}
+
+// marketPrice returns what a sale is worth at the given market multipliers
+func marketPrice(cur map[string]float64, worth int, stored []InvFish) int {
+	total := float64(worth)
+	for _, f := range stored {
+		total += math.Floor(f.Price*multiplier(cur, f.Name)) - f.Price
+	}
+	return int(total)
+}
+
+// queueMarketSale lowers the price of every species sold from the multipliers the sale was priced at,
+// the sale has to watch the market so the multipliers cannot change before it commits
+func queueMarketSale(pipe redis.Pipeliner, cur map[string]float64, stored []InvFish) {
+	sold := map[string]int{}
+	for _, f := range stored {
+		sold[f.Name]++
+	}
+	lo, hi := marketBounds()
+	for species, n := range sold {
+		m := multiplier(cur, species) - float64(n)*Market.SaleImpact
+		pipe.HSet(MarketKey, species, math.Max(lo, math.Min(hi, m)))
+	}
+}
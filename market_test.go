@@ -0,0 +1,73 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-redis/redis"
+)
+
+func TestSellFishPaysWalletAndLowersMarket(t *testing.T) {
+	newTestRedis(t)
+	defer func(impact float64) { Market.SaleImpact = impact }(Market.SaleImpact)
+	Market.SaleImpact = 0.1
+	redisClient.HSet(MarketKey, "Salmon", 1.5)
+	giveTestFish(t, "alice", InvFish{Name: "Salmon", Price: 40, Tier: 2})
+	giveTestFish(t, "alice", InvFish{Name: "Salmon", Price: 20, Tier: 2})
+	giveTestFish(t, "alice", InvFish{Name: "Cod", Price: 10, Tier: 1})
+
+	worth, yen := DBSellFish("alice")
+	if worth["worth"] != "70" {
+		t.Errorf("sold worth = %v, want 70", worth["worth"])
+	}
+	// salmon sells at 1.5 times its price and cod at its base price
+	if yen != 100 {
+		t.Errorf("yen = %v, want 100", yen)
+	}
+	if got := DBGetWallet("alice"); got != 100 {
+		t.Errorf("wallet = %v, want 100", got)
+	}
+	if n, w := fishCount("alice"); n != 0 || w != 0 {
+		t.Errorf("inventory = %v fish worth %v, want empty", n, w)
+	}
+	if got := len(DBGetStoredFish("alice")); got != 0 {
+		t.Errorf("%v fish are still stored", got)
+	}
+	market := DBGetMarket()
+	if got := market["Salmon"]; math.Abs(got-1.3) > 1e-9 {
+		t.Errorf("salmon multiplier = %v, want 1.3", got)
+	}
+	if got := market["Cod"]; math.Abs(got-0.9) > 1e-9 {
+		t.Errorf("cod multiplier = %v, want 0.9", got)
+	}
+}
+
+func TestSellFishAppliesPrestigeAndMarketFloor(t *testing.T) {
+	newTestRedis(t)
+	defer func(impact float64) { Market.SaleImpact = impact }(Market.SaleImpact)
+	Market.SaleImpact = 0.3
+	redisClient.HSet(MarketKey, "Cod", 0.6)
+	redisClient.ZAdd(PrestigeKey, redis.Z{Score: 2, Member: "alice"})
+	giveTestFish(t, "alice", InvFish{Name: "Cod", Price: 100, Tier: 1})
+
+	_, yen := DBSellFish("alice")
+	// 100 at a 0.6 multiplier is 60, with two prestige levels of yen bonus on top
+	want := int(60 * (1 + 2*Prestige.YenBonus))
+	if yen != want || DBGetWallet("alice") != want {
+		t.Errorf("yen = %v and wallet = %v, want %v", yen, DBGetWallet("alice"), want)
+	}
+	lo, _ := marketBounds()
+	if got := DBGetMarket()["Cod"]; got != lo {
+		t.Errorf("cod multiplier = %v, want the market floor %v", got, lo)
+	}
+}
+
+func TestSellFishWithNothingToSell(t *testing.T) {
+	newTestRedis(t)
+	if _, yen := DBSellFish("alice"); yen != 0 {
+		t.Errorf("yen = %v, want 0", yen)
+	}
+	if got := DBGetWallet("alice"); got != 0 {
+		t.Errorf("wallet = %v, want 0", got)
+	}
+}
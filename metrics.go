@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-redis/redis"
+)
+
+// metricLabels are the leaderboards kept besides exp and prestige and how their scores are labelled
+var metricLabels = map[string]string{
+	"yen":     "Yen Earned",
+	"biggest": "Biggest Catch (cm)",
+	"casts":   "Casts",
+	"garbage": "Garbage Collected",
+	"species": "Caught",
+}
+
+func init() {
+	onActivity(metricActivity)
+}
+
+// speciesMetric returns the metric counting catches of a species
+func speciesMetric(species string) string {
+	return "species:" + species
+}
+
+// metricActivity updates the metric leaderboards an activity counts towards,
+// activities made in a guild also update that guilds boards
+func metricActivity(a Activity) {
+	var metric string
+	var amt float64
+	switch a.Type {
+	case "cast":
+		metric, amt = "casts", 1
+	case "garbage":
+		metric, amt = "garbage", 1
+	case "sell":
+		metric, amt = "yen", float64(a.Amount)
+	case "fish":
+		metricFish(a.UserID, a.GuildID, a.Fish)
+		return
+	default:
+		return
+	}
+	incrMetric(a.UserID, a.GuildID, metric, amt)
+}
+
+// metricFish updates the biggest catch and species boards with a caught fish,
+// it is called directly for catches that emit no activity such as those of nets
+func metricFish(userID, guildID string, f InvFish) {
+	for _, key := range metricKeys(guildID, "biggest") {
+		if _, err := zaddMax(key, userID, f.Size); err != nil {
+			logError("Unable to update biggest catch board", err)
+		}
+	}
+	incrMetric(userID, guildID, speciesMetric(f.Name), 1)
+}
+
+func incrMetric(userID, guildID, metric string, amt float64) {
+	_, err := redisClient.Pipelined(func(pipe redis.Pipeliner) error {
+		for _, key := range metricKeys(guildID, metric) {
+			pipe.ZIncrBy(key, amt, userID)
+		}
+		return nil
+	})
+	if err != nil {
+		logError("Unable to update metric board", err)
+	}
+}
+
+// metricKeys returns the global board of a metric and the board of the guild an activity was made in
+func metricKeys(guildID, metric string) []string {
+	keys := []string{MetricKey(metric)}
+	if guildID != "" {
+		keys = append(keys, GuildMetricKey(guildID, metric))
+	}
+	return keys
+}
+
+// DBMetricBoard returns the key and label of a global or guild metric board,
+// yen is earned outside of guilds so its guild board is made from the global board like the aquarium board
+func DBMetricBoard(board, species string, global bool, guildID string) (string, string, error) {
+	label, ok := metricLabels[board]
+	if !ok {
+		return "", "", fmt.Errorf("Invalid leaderboard %s", board)
+	}
+	metric := board
+	if board == "species" {
+		if species == "" {
+			return "", "", errors.New("Species leaderboards need a species")
+		}
+		metric, label = speciesMetric(species), species+" "+label
+	}
+	if global {
+		return MetricKey(metric), label, nil
+	}
+	if guildID == "" {
+		return "", "", errors.New("Guild leaderboards need a guild")
+	}
+	key := GuildMetricKey(guildID, metric)
+	if board == "yen" {
+		return key, label, guildBoard(key, guildID, MetricKey(metric))
+	}
+	return key, label, nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/iopred/discordgo"
+)
+
+func netInterval() time.Duration {
+	if Nets.Interval < 1 {
+		return 10 * time.Minute
+	}
+	return time.Duration(Nets.Interval) * time.Minute
+}
+
+func netDurations() (int, int) {
+	shortest, longest := Nets.MinDuration, Nets.MaxDuration
+	if shortest < 1 {
+		shortest = 30
+	}
+	if longest < shortest {
+		longest = 720
+	}
+	return shortest, longest
+}
+
+// casts returns how many casts a net has made by a time, a net stops casting once it ends
+func (n Net) casts(at time.Time) int {
+	if at.After(n.Ends) {
+		at = n.Ends
+	}
+	if at.Before(n.Deployed) {
+		return 0
+	}
+	return int(at.Sub(n.Deployed) / netInterval())
+}
+
+// chance returns the probability of a roll against a rate succeeding
+func chance(rate int64) float64 {
+	return math.Max(0, math.Min(1, float64(rate+1)/99))
+}
+
+// netCast builds the cast a net makes from a users current gear and their density at the nets location
+func netCast(userID string, n Net) (cast, error) {
+	c := cast{user: &discordgo.User{ID: userID}, guildID: n.GuildID, loc: n.Location}
+	c.density, _ = DBGetLocDensity(userID)
+	c.bite = DBGetBiteRate(userID, c.density, c.loc)
+	var err error
+	if c.catch, err = DBGetCatchRate(userID); err != nil {
+		return c, err
+	}
+	if c.fish, err = DBGetFishRate(userID); err != nil {
+		return c, err
+	}
+	DBGetBuffs(userID).apply(&c)
+	return c, nil
+}
+
+// DBDeployNet deploys a net at a location for a number of minutes, a user can only have one net out at a time
+func DBDeployNet(userID string, req NetRequest) (Net, error) {
+	if req.GuildID == "" {
+		return Net{}, errors.New("Nets must be deployed from a guild")
+	}
+	if !validLocations[req.Location] {
+		return Net{}, fmt.Errorf("Invalid location %s", req.Location)
+	}
+	shortest, longest := netDurations()
+	if req.Duration < shortest || req.Duration > longest {
+		return Net{}, fmt.Errorf("Nets can be deployed for between %v and %v minutes", shortest, longest)
+	}
+	n := Net{
+		GuildID:  req.GuildID,
+		Location: req.Location,
+		Deployed: CurrentTime,
+		Ends:     CurrentTime.Add(time.Duration(req.Duration) * time.Minute),
+	}
+	data, err := json.Marshal(n)
+	if err != nil {
+		return Net{}, err
+	}
+	ok, err := redisClient.SetNX(NetKey(userID), data, 0).Result()
+	if err != nil {
+		return Net{}, err
+	}
+	if !ok {
+		return Net{}, errors.New("You already have a net deployed")
+	}
+	return n, nil
+}
+
+// DBGetNet returns a users deployed net
+func DBGetNet(userID string) (Net, error) {
+	n, _, err := netOf(redisClient, userID)
+	return n, err
+}
+
+// netOf reads a users deployed net with the given client along with how it is stored
+func netOf(c redis.Cmdable, userID string) (Net, string, error) {
+	var n Net
+	data, err := c.Get(NetKey(userID)).Result()
+	if err == redis.Nil {
+		return n, "", errors.New("You do not have a net deployed")
+	}
+	if err != nil {
+		return n, "", err
+	}
+	return n, data, json.Unmarshal([]byte(data), &n)
+}
+
+// DBGetNetYield returns how much a users net is expected to have caught so far with their current gear,
+// the expected fish are capped by the space left in their inventory
+func DBGetNetYield(userID string) (NetYield, error) {
+	n, err := DBGetNet(userID)
+	if err != nil {
+		return NetYield{}, err
+	}
+	c, err := netCast(userID, n)
+	if err != nil {
+		return NetYield{}, err
+	}
+	y := NetYield{
+		Net:   n,
+		Casts: n.casts(CurrentTime),
+		Space: DBGetInvCapacity(userID) - invSize(redisClient, userID),
+		Done:  !CurrentTime.Before(n.Ends),
+	}
+	if y.Space < 0 {
+		y.Space = 0
+	}
+	landed := float64(y.Casts) * chance(c.bite) * chance(c.catch)
+	y.Fish = math.Min(landed*chance(c.fish), float64(y.Space))
+	y.Garbage = landed * (1 - chance(c.fish))
+	return y, nil
+}
+
+// DBCollectNet pulls in a users net and lands everything it caught with their current gear,
+// the net is only removed once its catches have been landed
+func DBCollectNet(userID string) (BatchResult, error) {
+	n, data, err := netOf(redisClient, userID)
+	if err != nil {
+		return BatchResult{}, err
+	}
+	casts := n.casts(CurrentTime)
+	if casts < 1 {
+		return BatchResult{}, errors.New("Your net has not caught anything yet")
+	}
+	c, err := netCast(userID, n)
+	if err != nil {
+		return BatchResult{}, err
+	}
+	res, err := applyCasts(c, casts, false, data)
+	if err != nil {
+		return BatchResult{}, err
+	}
+	c.recordCatches(res)
+	go func() {
+		for _, b := range res.Results {
+			if b.Fish != nil {
+				metricFish(userID, n.GuildID, *b.Fish)
+			}
+		}
+	}()
+	return res, nil
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/iopred/discordgo"
+)
+
+func partyDuration() time.Duration {
+	if Parties.Duration < 1 {
+		return 2 * time.Hour
+	}
+	return time.Duration(Parties.Duration) * time.Minute
+}
+
+func partyLimit() int {
+	if Parties.MaxMembers < 2 {
+		return 5
+	}
+	return Parties.MaxMembers
+}
+
+// bonus returns how much a party adds to the bite rate of its members, every member besides the caster adds to it
+// so a party of one gets nothing
+func (p Party) bonus() int64 {
+	return Parties.BiteBonus * int64(len(p.Members)-1)
+}
+
+// rare reports whether a fish is rare enough to be shared with a party
+func rare(f InvFish) bool {
+	tier := Parties.RareTier
+	if tier < 1 {
+		tier = 4
+	}
+	return f.Tier >= tier
+}
+
+func partyOf(c redis.Cmdable, partyID string) (Party, error) {
+	var p Party
+	data, err := c.Get(PartyKey(partyID)).Result()
+	if err == redis.Nil {
+		return p, errors.New("That party has disbanded")
+	}
+	if err != nil {
+		return p, err
+	}
+	return p, json.Unmarshal([]byte(data), &p)
+}
+
+// queueParty stores a party and the membership of every member until the party expires
+func queueParty(pipe redis.Pipeliner, p Party) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	ttl := p.Expires.Sub(CurrentTime)
+	pipe.Set(PartyKey(p.ID), data, ttl)
+	for _, m := range p.Members {
+		pipe.Set(PartyUserKey(m), p.ID, ttl)
+	}
+	return nil
+}
+
+// DBGetUserParty returns the party a user is in
+func DBGetUserParty(userID string) (Party, error) {
+	id, err := redisClient.Get(PartyUserKey(userID)).Result()
+	if err == redis.Nil {
+		return Party{}, errors.New("You are not in a party")
+	}
+	if err != nil {
+		return Party{}, err
+	}
+	return partyOf(redisClient, id)
+}
+
+// DBGetParty returns a users party with its bonus and recent rare catches
+func DBGetParty(userID string) (PartyStatus, error) {
+	p, err := DBGetUserParty(userID)
+	if err != nil {
+		return PartyStatus{}, err
+	}
+	s := PartyStatus{Party: p, Bonus: p.bonus(), Catches: []PartyCatch{}}
+	for _, data := range redisClient.LRange(PartyFeedKey(p.ID), 0, -1).Val() {
+		var c PartyCatch
+		if err := json.Unmarshal([]byte(data), &c); err != nil {
+			logError("Unable to unmarshal party catch", err)
+			continue
+		}
+		s.Catches = append(s.Catches, c)
+	}
+	return s, nil
+}
+
+// DBCreateParty starts a new party in a guild led by a user
+func DBCreateParty(guildID, userID string) (Party, error) {
+	id, err := redisClient.Incr(PartyIDKey).Result()
+	if err != nil {
+		return Party{}, err
+	}
+	p := Party{
+		ID:      strconv.FormatInt(id, 10),
+		GuildID: guildID,
+		Leader:  userID,
+		Members: []string{userID},
+		Created: CurrentTime,
+		Expires: CurrentTime.Add(partyDuration()),
+	}
+	err = watchTx(func(tx *redis.Tx) error {
+		if tx.Exists(PartyUserKey(userID)).Val() > 0 {
+			return errors.New("You are already in a party")
+		}
+		_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+			return queueParty(pipe, p)
+		})
+		return err
+	}, PartyUserKey(userID))
+	return p, err
+}
+
+// DBJoinParty adds a user to a party of the same guild
+func DBJoinParty(guildID, userID, partyID string) (Party, error) {
+	var p Party
+	err := watchTx(func(tx *redis.Tx) error {
+		if tx.Exists(PartyUserKey(userID)).Val() > 0 {
+			return errors.New("You are already in a party")
+		}
+		var err error
+		if p, err = partyOf(tx, partyID); err != nil {
+			return err
+		}
+		if p.GuildID != guildID {
+			return errors.New("You can only join parties from your own guild")
+		}
+		if len(p.Members) >= partyLimit() {
+			return fmt.Errorf("Parties can only have %v members", partyLimit())
+		}
+		p.Members = append(p.Members, userID)
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			return queueParty(pipe, p)
+		})
+		return err
+	}, PartyKey(partyID), PartyUserKey(userID))
+	return p, err
+}
+
+// DBLeaveParty removes a user from their party, the next member leads once the leader leaves
+// and the party disbands once everyone has left
+func DBLeaveParty(userID string) error {
+	id, err := redisClient.Get(PartyUserKey(userID)).Result()
+	if err == redis.Nil {
+		return errors.New("You are not in a party")
+	}
+	if err != nil {
+		return err
+	}
+	return watchTx(func(tx *redis.Tx) error {
+		p, err := partyOf(tx, id)
+		if err != nil {
+			return err
+		}
+		members := []string{}
+		for _, m := range p.Members {
+			if m != userID {
+				members = append(members, m)
+			}
+		}
+		p.Members = members
+		if p.Leader == userID && len(members) > 0 {
+			p.Leader = members[0]
+		}
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.Del(PartyUserKey(userID))
+			if len(p.Members) == 0 {
+				pipe.Del(PartyKey(p.ID), PartyFeedKey(p.ID))
+				return nil
+			}
+			return queueParty(pipe, p)
+		})
+		return err
+	}, PartyKey(id), PartyUserKey(userID))
+}
+
+// castParty returns the party a user is casting with, a party only helps in its own guild
+func castParty(userID, guildID string) (Party, bool) {
+	p, err := DBGetUserParty(userID)
+	if err != nil || p.GuildID != guildID {
+		return Party{}, false
+	}
+	return p, true
+}
+
+// DBShareCatch shares a rare catch with the rest of a users party and returns the members that were notified
+func DBShareCatch(partyID, userID string, f InvFish) []string {
+	if partyID == "" || !rare(f) {
+		return nil
+	}
+	p, err := partyOf(redisClient, partyID)
+	if err != nil {
+		return nil
+	}
+	notified := []string{}
+	for _, m := range p.Members {
+		if m != userID {
+			notified = append(notified, m)
+		}
+	}
+	data, err := json.Marshal(PartyCatch{userID, f, CurrentTime})
+	if err != nil {
+		logError("Unable to marshal party catch", err)
+		return notified
+	}
+	key := PartyFeedKey(p.ID)
+	_, err = redisClient.Pipelined(func(pipe redis.Pipeliner) error {
+		pipe.LPush(key, data)
+		pipe.LTrim(key, 0, 19)
+		pipe.ExpireAt(key, p.Expires)
+		return nil
+	})
+	if err != nil {
+		logError("Unable to share party catch", err)
+	}
+	return notified
+}
+
+// shareBatch shares the rare catches of a batch with a users party and returns the members that were notified
+func (c cast) shareBatch(res BatchResult) []string {
+	var notified []string
+	for _, b := range res.Results {
+		if b.Fish == nil {
+			continue
+		}
+		if n := DBShareCatch(c.party, c.user.ID, *b.Fish); n != nil {
+			notified = n
+		}
+	}
+	return notified
+}
+
+func addPartyField(embed *discordgo.MessageEmbed, user string, notified []string) *discordgo.MessageEmbed {
+	if len(notified) == 0 {
+		return embed
+	}
+	mentions := make([]string, len(notified))
+	for i, m := range notified {
+		mentions[i] = "<@" + m + ">"
+	}
+	embed.Fields = append(embed.Fields,
+		&discordgo.MessageEmbedField{Name: "Party", Value: fmt.Sprintf("%s, %s made a rare catch!", strings.Join(mentions, " "), user), Inline: false},
+	)
+	return embed
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis"
+)
+
+// prestigeResetItems are the item categories a user loses when they prestige
+var prestigeResetItems = []string{"bait", "rod", "hook", "vehicle", "baitbox"}
+
+func prestigeRequirement() float64 {
+	if Prestige.MinExp <= 0 {
+		return 1000
+	}
+	return Prestige.MinExp
+}
+
+// DBGetPrestigeLevel returns how many times a user has prestiged
+func DBGetPrestigeLevel(userID string) int {
+	return prestigeLevel(redisClient, userID)
+}
+
+// prestigeLevel reads a users prestige level with the given client so it can be used inside transactions
+func prestigeLevel(c redis.Cmdable, userID string) int {
+	return int(c.ZScore(PrestigeKey, userID).Val())
+}
+
+// prestigeBadge returns the badge of a prestige level, the last badge repeats once the list runs out
+func prestigeBadge(level int) string {
+	if level < 1 || len(Prestige.Badges) == 0 {
+		return ""
+	}
+	if level > len(Prestige.Badges) {
+		level = len(Prestige.Badges)
+	}
+	return Prestige.Badges[level-1]
+}
+
+// prestigeExpMultiplier returns the permanent exp multiplier a user has earned by prestiging
+func prestigeExpMultiplier(userID string) float64 {
+	return 1 + Prestige.ExpBonus*float64(DBGetPrestigeLevel(userID))
+}
+
+// prestigeYenMultiplier returns the permanent sell price multiplier a user has earned by prestiging,
+// read with the given client so a sale can be priced inside its transaction
+func prestigeYenMultiplier(c redis.Cmdable, userID string) float64 {
+	return 1 + Prestige.YenBonus*float64(prestigeLevel(c, userID))
+}
+
+// DBGetPrestige returns a users prestige level, bonuses and history
+func DBGetPrestige(userID string) PrestigeStatus {
+	level := DBGetPrestigeLevel(userID)
+	status := PrestigeStatus{
+		Level:         level,
+		Badge:         prestigeBadge(level),
+		ExpMultiplier: prestigeExpMultiplier(userID),
+		YenMultiplier: prestigeYenMultiplier(redisClient, userID),
+		Required:      prestigeRequirement(),
+		Eligible:      DBGetGlobalScore(userID) >= prestigeRequirement(),
+		History:       []PrestigeRecord{},
+	}
+	for _, data := range redisClient.LRange(PrestigeHist(userID), 0, -1).Val() {
+		var p PrestigeRecord
+		if err := json.Unmarshal([]byte(data), &p); err != nil {
+			logError("Unable to unmarshal prestige record", err)
+			continue
+		}
+		status.History = append(status.History, p)
+	}
+	return status
+}
+
+// DBPrestige resets a users global score, equipment and inventory and raises their prestige level,
+// guild exp is kept as it is also what the users guilds have earned towards their clubs
+func DBPrestige(userID string) (PrestigeRecord, error) {
+	var rec PrestigeRecord
+	err := watchTx(func(tx *redis.Tx) error {
+		exp := tx.ZScore(ScoreGlobalKey, userID).Val()
+		if exp < prestigeRequirement() {
+			return fmt.Errorf("You need %v exp to prestige", prestigeRequirement())
+		}
+		rec = PrestigeRecord{int(tx.ZScore(PrestigeKey, userID).Val()) + 1, exp, CurrentTime}
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			// only the exp read is taken away so exp granted meanwhile is kept without watching the whole board
+			pipe.ZIncrBy(ScoreGlobalKey, -exp, userID)
+			pipe.ZAdd(PrestigeKey, redis.Z{Score: float64(rec.Level), Member: userID})
+			for _, item := range prestigeResetItems {
+				pipe.HSet(InventoryKey(userID), item, 0)
+				pipe.Del(OwnedItemKey(userID, item))
+			}
+			pipe.Del(FishInvKey(userID), CatchesKey(userID), BaitInvKey(userID), BaitTierKey(userID), DurabilityKey(userID))
+			pipe.RPush(PrestigeHist(userID), data)
+			return nil
+		})
+		return err
+	}, InventoryKey(userID), PrestigeKey, FishInvKey(userID), CatchesKey(userID), BaitInvKey(userID), BaitTierKey(userID), DurabilityKey(userID))
+	return rec, err
+}
+
+// DBPrestigeBoard returns the key of the global prestige board or of a guilds prestige board
+func DBPrestigeBoard(global bool, guildID string) (string, error) {
+	if global {
+		return PrestigeKey, nil
+	}
+	key := PrestigeTopKey(guildID)
+	return key, guildBoard(key, guildID, PrestigeKey)
+}
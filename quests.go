@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	pRand "math/rand"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+var questPeriods = []string{"daily", "weekly"}
+
+func init() {
+	onActivity(questActivity)
+}
+
+// questActivity advances every quest on a users current boards that an activity counts towards
+func questActivity(a Activity) {
+	for _, period := range questPeriods {
+		board, err := DBGetQuestBoard(a.UserID, period)
+		if err != nil {
+			logError("Unable to retrieve quest board", err)
+			continue
+		}
+		key := QuestProgKey(a.UserID, board.Key)
+		for _, q := range board.Quests {
+			if _, _, err := advanceRule(key, q.ID, q.Rule, a); err != nil {
+				logError("Unable to advance quest progress", err)
+			}
+		}
+		redisClient.Expire(key, questExpiration(period))
+	}
+}
+
+// questPeriodKey returns the key identifying the daily or weekly period a time falls in
+func questPeriodKey(period string, t time.Time) string {
+	if period == "weekly" {
+		y, w := t.ISOWeek()
+		return fmt.Sprintf("weekly:%d-W%02d", y, w)
+	}
+	return "daily:" + t.Format("2006-01-02")
+}
+
+// questPeriodEnd returns when the daily or weekly period a time falls in resets
+func questPeriodEnd(period string, t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if period == "weekly" {
+		// weeks reset on monday
+		offset := (8 - int(day.Weekday())) % 7
+		if offset == 0 {
+			offset = 7
+		}
+		return day.AddDate(0, 0, offset)
+	}
+	return day.AddDate(0, 0, 1)
+}
+
+// questExpiration returns how long the keys of the current period should be kept
+func questExpiration(period string) time.Duration {
+	return questPeriodEnd(period, CurrentTime).Sub(CurrentTime) + 24*time.Hour
+}
+
+func questConfig(period string) QuestPeriodConfig {
+	if period == "weekly" {
+		return Quests.Weekly
+	}
+	return Quests.Daily
+}
+
+// pickQuests randomly selects up to n templates of a period that aren't excluded
+func pickQuests(period string, n int, exclude map[string]bool) []QuestTemplate {
+	var pool []QuestTemplate
+	for _, t := range Quests.Templates {
+		if t.Period == period && !exclude[t.ID] {
+			pool = append(pool, t)
+		}
+	}
+	picked := []QuestTemplate{}
+	for _, i := range pRand.Perm(len(pool)) {
+		if len(picked) >= n {
+			break
+		}
+		picked = append(picked, pool[i])
+	}
+	return picked
+}
+
+// DBGetQuestBoard returns a users quests for the current period, generating them if they don't exist yet
+func DBGetQuestBoard(userID, period string) (QuestBoard, error) {
+	var board QuestBoard
+	pk := questPeriodKey(period, CurrentTime)
+	key := QuestKey(userID, pk)
+	data, err := redisClient.Get(key).Result()
+	if err == nil {
+		err = json.Unmarshal([]byte(data), &board)
+		return board, err
+	}
+	board = QuestBoard{pk, 0, pickQuests(period, questConfig(period).Count, nil)}
+	set, err := json.Marshal(board)
+	if err != nil {
+		return QuestBoard{}, err
+	}
+	ok, err := redisClient.SetNX(key, set, questExpiration(period)).Result()
+	if err != nil {
+		return QuestBoard{}, err
+	}
+	if !ok {
+		// another request generated the board first
+		return DBGetQuestBoard(userID, period)
+	}
+	return board, nil
+}
+
+// DBGetQuestStatus returns a users progress towards their quests for the current period
+func DBGetQuestStatus(userID, period string) (QuestBoardStatus, error) {
+	board, err := DBGetQuestBoard(userID, period)
+	if err != nil {
+		return QuestBoardStatus{}, err
+	}
+	status := QuestBoardStatus{
+		Period:      period,
+		Resets:      questPeriodEnd(period, CurrentTime),
+		RerollsLeft: questConfig(period).Rerolls - board.Rerolls,
+		Quests:      []QuestStatus{},
+	}
+	if status.RerollsLeft < 0 {
+		status.RerollsLeft = 0
+	}
+	progress := redisClient.HGetAll(QuestProgKey(userID, board.Key)).Val()
+	claimed := redisClient.SMembers(QuestClaimKey(userID, board.Key)).Val()
+	for _, q := range board.Quests {
+		qs := QuestStatus{QuestTemplate: q, Goal: q.Rule.goal()}
+		qs.Progress, _ = strconv.Atoi(progress[q.ID])
+		for _, c := range claimed {
+			if c == q.ID {
+				qs.Claimed = true
+			}
+		}
+		status.Quests = append(status.Quests, qs)
+	}
+	return status, nil
+}
+
+// findQuest returns the board, period and position of a quest on a users current boards
+func findQuest(userID, questID string) (QuestBoard, string, int, error) {
+	for _, period := range questPeriods {
+		board, err := DBGetQuestBoard(userID, period)
+		if err != nil {
+			return QuestBoard{}, "", -1, err
+		}
+		for i, q := range board.Quests {
+			if q.ID == questID {
+				return board, period, i, nil
+			}
+		}
+	}
+	return QuestBoard{}, "", -1, errors.New("Quest not found")
+}
+
+// DBClaimQuest pays out the reward of a completed quest once, the claim and its reward are made in one transaction
+func DBClaimQuest(userID, questID string) (QuestTemplate, error) {
+	board, period, i, err := findQuest(userID, questID)
+	if err != nil {
+		return QuestTemplate{}, err
+	}
+	q := board.Quests[i]
+	prog := QuestProgKey(userID, board.Key)
+	claims := QuestClaimKey(userID, board.Key)
+	err = watchTx(func(tx *redis.Tx) error {
+		progress, _ := strconv.Atoi(tx.HGet(prog, q.ID).Val())
+		if progress < q.Rule.goal() {
+			return errors.New("Quest is not complete yet")
+		}
+		if tx.SIsMember(claims, q.ID).Val() {
+			return errors.New("Quest has already been claimed")
+		}
+		_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.SAdd(claims, q.ID)
+			pipe.Expire(claims, questExpiration(period))
+			return queueReward(tx, pipe, userID, q.Reward)
+		})
+		return err
+	}, append([]string{prog, claims}, rewardKeys(userID, q.Reward)...)...)
+	return q, err
+}
+
+// DBRerollQuest replaces an unclaimed quest with a different one from the same period
+func DBRerollQuest(userID, questID string) (QuestTemplate, error) {
+	found, period, _, err := findQuest(userID, questID)
+	if err != nil {
+		return QuestTemplate{}, err
+	}
+	key := QuestKey(userID, found.Key)
+	claims := QuestClaimKey(userID, found.Key)
+	var picked QuestTemplate
+	err = watchTx(func(tx *redis.Tx) error {
+		var board QuestBoard
+		data, err := tx.Get(key).Result()
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(data), &board); err != nil {
+			return err
+		}
+		i := -1
+		exclude := map[string]bool{}
+		for j, q := range board.Quests {
+			if q.ID == questID {
+				i = j
+			}
+			exclude[q.ID] = true
+		}
+		if i < 0 {
+			return errors.New("Quest not found")
+		}
+		if board.Rerolls >= questConfig(period).Rerolls {
+			return errors.New("No rerolls left for this period")
+		}
+		if tx.SIsMember(claims, questID).Val() {
+			return errors.New("Claimed quests cannot be rerolled")
+		}
+		choices := pickQuests(period, 1, exclude)
+		if len(choices) == 0 {
+			return errors.New("No other quests are available")
+		}
+		picked = choices[0]
+		board.Quests[i] = picked
+		board.Rerolls++
+		set, err := json.Marshal(board)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.HDel(QuestProgKey(userID, board.Key), questID, picked.ID)
+			pipe.Set(key, set, questExpiration(period))
+			return nil
+		})
+		return err
+	}, key, claims)
+	return picked, err
+}
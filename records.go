@@ -0,0 +1,78 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/go-redis/redis"
+)
+
+// recordScript raises a users best size for a species on the guild and global boards
+// and returns whether the catch beat their personal best, the guild record and the global record
+var recordScript = redis.NewScript(`
+local size = tonumber(ARGV[2])
+local flags = {0, 0, 0}
+local best = tonumber(redis.call("ZSCORE", KEYS[2], ARGV[1]))
+if best == nil or size > best then
+	flags[1] = 1
+end
+for i, key in ipairs(KEYS) do
+	local top = redis.call("ZREVRANGE", key, 0, 0, "WITHSCORES")
+	if top[2] == nil or size > tonumber(top[2]) then
+		flags[i + 1] = 1
+	end
+	local cur = tonumber(redis.call("ZSCORE", key, ARGV[1]))
+	if cur == nil or size > cur then
+		redis.call("ZADD", key, size, ARGV[1])
+	end
+end
+return flags
+`)
+
+// DBUpdateRecords submits a catch to the species record boards and returns the records it broke
+func DBUpdateRecords(userID, guildID string, f InvFish) (RecordFlags, error) {
+	res, err := recordScript.Run(redisClient,
+		[]string{RecordGuildKey(guildID, f.Name), RecordGlobKey(f.Name)},
+		userID, f.Size,
+	).Result()
+	if err != nil {
+		return RecordFlags{}, err
+	}
+	flags, ok := res.([]interface{})
+	if !ok || len(flags) != 3 {
+		return RecordFlags{}, nil
+	}
+	return RecordFlags{
+		Personal: flags[0] == int64(1),
+		Guild:    flags[1] == int64(1),
+		Global:   flags[2] == int64(1),
+	}, nil
+}
+
+// DBGetRecordBoard returns the top 10 record holders of a species on a board
+func DBGetRecordBoard(key, species string) (RecordBoard, error) {
+	board := RecordBoard{species, []RecordHolder{}}
+	z, err := redisClient.ZRevRangeWithScores(key, 0, 9).Result()
+	if err != nil {
+		return board, err
+	}
+	for i, e := range z {
+		user := e.Member.(string)
+		board.Holders = append(board.Holders, RecordHolder{int64(i + 1), user, DBGetTrackedUser(user), e.Score})
+	}
+	return board, nil
+}
+
+// DBGetUserRecords returns a users best size and global rank for every species they have caught
+func DBGetUserRecords(userID string) []UserRecord {
+	records := []UserRecord{}
+	for name := range DBGetFishdex(userID) {
+		key := RecordGlobKey(name)
+		size, err := redisClient.ZScore(key, userID).Result()
+		if err != nil {
+			continue
+		}
+		records = append(records, UserRecord{name, size, redisClient.ZRevRank(key, userID).Val() + 1})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Species < records[j].Species })
+	return records
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	pRand "math/rand"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/iopred/discordgo"
+)
+
+// roll makes a single roll against a rate the same way fishCatch does
+func roll(rate int64) bool {
+	var r int64
+	if n, err := rand.Int(rand.Reader, big.NewInt(99)); err == nil {
+		r = n.Int64()
+	}
+	return r <= rate
+}
+
+func reelToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func reelExpiry() time.Duration {
+	if Reel.Expiry < 1 {
+		return 30 * time.Second
+	}
+	return time.Duration(Reel.Expiry) * time.Second
+}
+
+// reelWindow returns how many milliseconds a player has to react to a bite,
+// rarer fish give less time and rods with a better catch rate give more
+func reelWindow(tier int, catch int64) int {
+	window := 2000
+	if tier >= 1 && tier <= len(Reel.Windows) {
+		window = Reel.Windows[tier-1]
+	}
+	return int(float64(window) * float64(catch) / 50)
+}
+
+// reelConfigured makes sure there are actions to ask players for, it is checked before a reel cast is made
+func reelConfigured() error {
+	if len(Reel.Actions) == 0 {
+		return errors.New("No reel actions are configured")
+	}
+	return nil
+}
+
+// DBStartReel stores a reel session for a cast that got a bite, replacing any session the user already had
+func DBStartReel(c cast, tier int) (ReelBite, error) {
+	if err := reelConfigured(); err != nil {
+		return ReelBite{}, err
+	}
+	token, err := reelToken()
+	if err != nil {
+		return ReelBite{}, err
+	}
+	a := Reel.Actions[pRand.Intn(len(Reel.Actions))]
+	s := ReelSession{
+		Token:    token,
+		UserID:   c.user.ID,
+		Username: c.user.Username,
+		GuildID:  c.guildID,
+		Location: c.loc,
+		Density:  c.density,
+		Bite:     c.bite,
+		Catch:    c.catch,
+		Fish:     c.fish,
+		Tier:     tier,
+		Action:   a.Action,
+		Window:   reelWindow(tier, c.catch),
+		Started:  time.Now().UTC(),
+		Party:    c.party,
+	}
+	if err := marshalAndSet(s, ReelKey(s.UserID), reelExpiry()); err != nil {
+		return ReelBite{}, err
+	}
+	return ReelBite{s.Token, a.Cue, s.Window, s.Started.Add(reelExpiry())}, nil
+}
+
+// DBTakeReel removes and returns a users reel session if the token matches it
+func DBTakeReel(userID, token string) (ReelSession, error) {
+	var s ReelSession
+	key := ReelKey(userID)
+	err := watchTx(func(tx *redis.Tx) error {
+		data, err := tx.Get(key).Result()
+		if err == redis.Nil {
+			return errors.New("Your line went slack and the fish got away")
+		}
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(data), &s); err != nil {
+			return err
+		}
+		if s.Token != token {
+			return errors.New("That bite is no longer on your line")
+		}
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.Del(key)
+			return nil
+		})
+		return err
+	}, key)
+	return s, err
+}
+
+// cast rebuilds the cast a reel session was started from
+func (s ReelSession) cast() cast {
+	return cast{
+		user:    &discordgo.User{ID: s.UserID, Username: s.Username},
+		guildID: s.GuildID,
+		loc:     s.Location,
+		density: s.Density,
+		bite:    s.Bite,
+		catch:   s.Catch,
+		fish:    s.Fish,
+		party:   s.Party,
+		buffs:   DBGetBuffs(s.UserID),
+	}
+}
+
+// reeled reports whether a player reacted to a bite in time with the right action
+func (s ReelSession) reeled(action string, at time.Time) bool {
+	return action == s.Action && at.Sub(s.Started) <= time.Duration(s.Window)*time.Millisecond
+}
@@ -26,6 +26,24 @@ var routes = Routes{
 		"/v1/fish/{guildID}",
 		Fishy,
 	},
+	Route{
+		"BatchFish",
+		"POST",
+		"/v1/fish/{guildID}/batch",
+		BatchFish,
+	},
+	Route{
+		"StartReel",
+		"POST",
+		"/v1/fish/{guildID}/reel",
+		StartReel,
+	},
+	Route{
+		"FinishReel",
+		"POST",
+		"/v1/fish/{guildID}/reel/{userID}",
+		FinishReel,
+	},
 	Route{
 		"GetLocation",
 		"GET",
@@ -50,6 +68,12 @@ var routes = Routes{
 		"/v1/inventory/{userID}",
 		BuyItem,
 	},
+	Route{
+		"EquipItem",
+		"POST",
+		"/v1/inventory/{userID}/equip",
+		EquipItem,
+	},
 	Route{
 		"Blacklist",
 		"GET",
@@ -140,4 +164,292 @@ var routes = Routes{
 		"/v1/stats/{guildID}/{userID}",
 		Stats,
 	},
+	Route{
+		"Events",
+		"GET",
+		"/v1/events",
+		ListEvents,
+	},
+	Route{
+		"CreateEvent",
+		"POST",
+		"/v1/events",
+		CreateEvent,
+	},
+	Route{
+		"DeleteEvent",
+		"DELETE",
+		"/v1/events/{eventID}",
+		DeleteEvent,
+	},
+	Route{
+		"Fishdex",
+		"GET",
+		"/v1/fishdex/{userID}",
+		Fishdex,
+	},
+	Route{
+		"GlobalRecords",
+		"GET",
+		"/v1/records/global/{species}",
+		GlobalRecords,
+	},
+	Route{
+		"GuildRecords",
+		"GET",
+		"/v1/records/guild/{guildID}/{species}",
+		GuildRecords,
+	},
+	Route{
+		"UserRecords",
+		"GET",
+		"/v1/records/user/{userID}",
+		UserRecords,
+	},
+	Route{
+		"Achievements",
+		"GET",
+		"/v1/achievements/{userID}",
+		Achievements,
+	},
+	Route{
+		"Quests",
+		"GET",
+		"/v1/quests/{userID}",
+		GetQuests,
+	},
+	Route{
+		"ClaimQuest",
+		"POST",
+		"/v1/quests/{userID}/{questID}/claim",
+		ClaimQuest,
+	},
+	Route{
+		"RerollQuest",
+		"POST",
+		"/v1/quests/{userID}/{questID}/reroll",
+		RerollQuest,
+	},
+	Route{
+		"Daily",
+		"GET",
+		"/v1/daily/{userID}",
+		DailyGet,
+	},
+	Route{
+		"Daily",
+		"POST",
+		"/v1/daily/{userID}",
+		DailyClaim,
+	},
+	Route{
+		"CreateTournament",
+		"POST",
+		"/v1/tournaments/{guildID}",
+		CreateTournament,
+	},
+	Route{
+		"Tournaments",
+		"GET",
+		"/v1/tournaments/{guildID}",
+		ListTournaments,
+	},
+	Route{
+		"TournamentArchive",
+		"GET",
+		"/v1/tournaments/{guildID}/archive",
+		TournamentArchive,
+	},
+	Route{
+		"TournamentStandings",
+		"GET",
+		"/v1/tournaments/{guildID}/{tournamentID}",
+		TournamentStandings,
+	},
+	Route{
+		"StoredFish",
+		"GET",
+		"/v1/inventory/{userID}/fish",
+		StoredFish,
+	},
+	Route{
+		"CreateTrade",
+		"POST",
+		"/v1/trades/{userID}",
+		CreateTrade,
+	},
+	Route{
+		"Trades",
+		"GET",
+		"/v1/trades/{userID}",
+		GetTrades,
+	},
+	Route{
+		"AcceptTrade",
+		"POST",
+		"/v1/trades/{userID}/{tradeID}/accept",
+		AcceptTrade,
+	},
+	Route{
+		"DeclineTrade",
+		"POST",
+		"/v1/trades/{userID}/{tradeID}/decline",
+		DeclineTrade,
+	},
+	Route{
+		"Market",
+		"GET",
+		"/v1/market",
+		GetMarket,
+	},
+	Route{
+		"Auctions",
+		"GET",
+		"/v1/auctions",
+		ListAuctions,
+	},
+	Route{
+		"CreateAuction",
+		"POST",
+		"/v1/auctions/{userID}",
+		CreateAuction,
+	},
+	Route{
+		"UserAuctions",
+		"GET",
+		"/v1/auctions/{userID}",
+		UserAuctions,
+	},
+	Route{
+		"BidAuction",
+		"POST",
+		"/v1/auctions/{userID}/{auctionID}/bid",
+		BidAuction,
+	},
+	Route{
+		"AquariumLeaderboard",
+		"GET",
+		"/v1/aquarium/leaderboard/{guildID}",
+		AquariumLeaderboard,
+	},
+	Route{
+		"Aquarium",
+		"GET",
+		"/v1/aquarium/{userID}",
+		GetAquarium,
+	},
+	Route{
+		"MoveAquarium",
+		"POST",
+		"/v1/aquarium/{userID}/{action:add|remove}",
+		MoveAquarium,
+	},
+	Route{
+		"Recipes",
+		"GET",
+		"/v1/recipes",
+		GetRecipes,
+	},
+	Route{
+		"Craft",
+		"POST",
+		"/v1/craft/{userID}/{recipeID}",
+		Craft,
+	},
+	Route{
+		"Buffs",
+		"GET",
+		"/v1/buffs/{userID}",
+		GetBuffs,
+	},
+	Route{
+		"UseConsumable",
+		"POST",
+		"/v1/consumables/{userID}/{consumableID}/use",
+		UseConsumable,
+	},
+	Route{
+		"RepairItem",
+		"POST",
+		"/v1/repair/{userID}/{category:rod|hook}",
+		RepairItem,
+	},
+	Route{
+		"Prestige",
+		"GET",
+		"/v1/prestige/{userID}",
+		GetPrestige,
+	},
+	Route{
+		"DoPrestige",
+		"POST",
+		"/v1/prestige/{userID}",
+		DoPrestige,
+	},
+	Route{
+		"Net",
+		"GET",
+		"/v1/nets/{userID}",
+		GetNet,
+	},
+	Route{
+		"DeployNet",
+		"POST",
+		"/v1/nets/{userID}",
+		DeployNet,
+	},
+	Route{
+		"CollectNet",
+		"POST",
+		"/v1/nets/{userID}/collect",
+		CollectNet,
+	},
+	Route{
+		"Party",
+		"GET",
+		"/v1/party/{userID}",
+		GetParty,
+	},
+	Route{
+		"LeaveParty",
+		"DELETE",
+		"/v1/party/{userID}",
+		LeaveParty,
+	},
+	Route{
+		"CreateParty",
+		"POST",
+		"/v1/party/{guildID}/{userID}",
+		CreateParty,
+	},
+	Route{
+		"JoinParty",
+		"POST",
+		"/v1/party/{guildID}/{userID}/join/{partyID}",
+		JoinParty,
+	},
+	Route{
+		"Gifts",
+		"GET",
+		"/v1/gifts/{userID}",
+		GetGifts,
+	},
+	Route{
+		"SendGift",
+		"POST",
+		"/v1/gifts/{userID}/{recipientID}",
+		SendGift,
+	},
+	Route{
+		"Club",
+		"GET",
+		"/v1/clubs/{guildID}",
+		GetClub,
+	},
+	Route{
+		"EditClub",
+		"PUT",
+		"/v1/clubs/{guildID}",
+		EditClub,
+	},
 }
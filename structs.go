@@ -4,42 +4,36 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"log"
+	"os"
+	"time"
+
+	"github.com/iopred/discordgo"
 )
 
 // FishData holds the JSON structure for fish.json
 type FishData struct {
 	Location struct {
-		Lake []struct {
-			Fish []struct {
-				Image string      `json:"image"`
-				Name  string      `json:"name"`
-				Pun   string      `json:"pun"`
-				Size  []int       `json:"size"`
-				Time  interface{} `json:"time"`
-			} `json:"fish"`
-		} `json:"lake"`
-		Ocean []struct {
-			Fish []struct {
-				Image string      `json:"image"`
-				Name  string      `json:"name"`
-				Pun   string      `json:"pun"`
-				Size  []int       `json:"size"`
-				Time  interface{} `json:"time"`
-			} `json:"fish"`
-		} `json:"ocean"`
-		River []struct {
-			Fish []struct {
-				Image string      `json:"image"`
-				Name  string      `json:"name"`
-				Pun   string      `json:"pun"`
-				Size  []int       `json:"size"`
-				Time  interface{} `json:"time"`
-			} `json:"fish"`
-		} `json:"river"`
+		Lake  []FishTier `json:"lake"`
+		Ocean []FishTier `json:"ocean"`
+		River []FishTier `json:"river"`
 	} `json:"location"`
 	Prices [][]float64
 }
 
+// FishTier holds every fish of a single tier for a location
+type FishTier struct {
+	Fish []FishSpecies `json:"fish"`
+}
+
+// FishSpecies holds the JSON structure for a single fish in fish.json
+type FishSpecies struct {
+	Image string      `json:"image"`
+	Name  string      `json:"name"`
+	Pun   string      `json:"pun"`
+	Size  []int       `json:"size"`
+	Time  interface{} `json:"time"`
+}
+
 // TrashData stores the data structure for trash data
 type TrashData struct {
 	Regular struct {
@@ -55,6 +49,7 @@ type TrashData struct {
 
 // InvFish holds the JSON structure for a singular fish
 type InvFish struct {
+	ID       string  `json:"id,omitempty"`
 	Location string  `json:"location"`
 	Name     string  `json:"name"`
 	Price    float64 `json:"price"`
@@ -89,6 +84,8 @@ type ItemData struct {
 		Cost        int     `json:"cost"`
 		Effect      float64 `json:"effect"`
 		Description string  `json:"description"`
+		Durability  int     `json:"durability,omitempty"`
+		RepairCost  int     `json:"repair_cost,omitempty"`
 	} `json:"rod"`
 	Hook []struct {
 		Name        string  `json:"name"`
@@ -98,6 +95,8 @@ type ItemData struct {
 		Effect      float64 `json:"effect,omitempty"`
 		Description string  `json:"description"`
 		Modifier    float64 `json:"modifier,omitempty"`
+		Durability  int     `json:"durability,omitempty"`
+		RepairCost  int     `json:"repair_cost,omitempty"`
 	} `json:"hook"`
 	Vehicle []struct {
 		Name        string `json:"name"`
@@ -115,6 +114,22 @@ type ItemData struct {
 		Effect      int    `json:"effect"`
 		Description string `json:"description"`
 	} `json:"bait_box"`
+	Aquarium []struct {
+		Name        string `json:"name"`
+		ID          int    `json:"id"`
+		Tier        int    `json:"tier"`
+		Cost        int    `json:"cost"`
+		Effect      int    `json:"effect"`
+		Description string `json:"description"`
+	} `json:"aquarium"`
+	AutoFisher []struct {
+		Name        string `json:"name"`
+		ID          int    `json:"id"`
+		Tier        int    `json:"tier"`
+		Cost        int    `json:"cost"`
+		Effect      int    `json:"effect"`
+		Description string `json:"description"`
+	} `json:"auto_fisher"`
 }
 
 // UserItems holds the JSON structure for a users items
@@ -128,11 +143,13 @@ type ItemData struct {
 
 // UserItems stores all the item categories for a specific user
 type UserItems struct {
-	Bait    UserItem `json:"bait"`
-	Rod     UserItem `json:"rod"`
-	Hook    UserItem `json:"hook"`
-	Vehicle UserItem `json:"vehicle"`
-	BaitBox UserItem `json:"bait_box"`
+	Bait       UserItem `json:"bait"`
+	Rod        UserItem `json:"rod"`
+	Hook       UserItem `json:"hook"`
+	Vehicle    UserItem `json:"vehicle"`
+	BaitBox    UserItem `json:"bait_box"`
+	Aquarium   UserItem `json:"aquarium"`
+	AutoFisher UserItem `json:"auto_fisher"`
 }
 
 // UserItem stores the data for each item category
@@ -195,6 +212,12 @@ type BuyItemRequest struct {
 	Owned    []int  `json:"owned"`
 }
 
+// EquipRequest stores the data for the equip endpoint
+type EquipRequest struct {
+	Category string `json:"category"`
+	Item     int    `json:"item"`
+}
+
 // APIResponse is a standard API response
 type APIResponse struct {
 	Error   bool        `json:"error"`
@@ -209,6 +232,10 @@ type LeaderboardRequest struct {
 	User      string `json:"user"`
 	GuildID   string `json:"guildid,omitempty"`
 	GuildName string `json:"guildname,omitempty"`
+	Board     string `json:"board,omitempty"`
+	Period    string `json:"period,omitempty"`
+	Previous  bool   `json:"previous,omitempty"`
+	Species   string `json:"species,omitempty"`
 }
 
 //
@@ -218,6 +245,7 @@ type LeaderboardData struct {
 	Score     float64
 	GuildName string
 	Global    bool
+	Label     string
 }
 
 //
@@ -253,6 +281,665 @@ type CommandStatData struct {
 	Total  int `json:"total"`
 }
 
+// EventData holds the JSON structure for events.json
+type EventData struct {
+	Events []Event `json:"events"`
+}
+
+// Event stores a time-bounded event and the modifiers it applies while active
+type Event struct {
+	ID            string      `json:"id"`
+	Name          string      `json:"name"`
+	Description   string      `json:"description"`
+	Start         time.Time   `json:"start"`
+	End           time.Time   `json:"end"`
+	Locations     []string    `json:"locations"`
+	ExpMultiplier float64     `json:"exp_multiplier"`
+	Fish          []EventFish `json:"fish"`
+	Trash         []string    `json:"trash"`
+	Buffs         []BuffGrant `json:"buffs"`
+}
+
+// EventFish is a limited-time fish added to the pool of its tier during an event
+type EventFish struct {
+	FishSpecies
+	Tier int `json:"tier"`
+}
+
+// EventList stores the data for the events endpoint
+type EventList struct {
+	Active   []Event `json:"active"`
+	Upcoming []Event `json:"upcoming"`
+}
+
+// Reward stores the payout for reaching a goal
+type Reward struct {
+	Bait  []BaitRequest `json:"bait,omitempty"`
+	Yen   int           `json:"yen,omitempty"`
+	Exp   float64       `json:"exp,omitempty"`
+	Buffs []BuffGrant   `json:"buffs,omitempty"`
+}
+
+// FishdexConfig holds the JSON structure for fishdex.json
+type FishdexConfig struct {
+	Rewards []FishdexReward `json:"rewards"`
+}
+
+// FishdexReward stores a reward unlocked at a fishdex completion percentage
+type FishdexReward struct {
+	Completion float64 `json:"completion"`
+	Reward     Reward  `json:"reward"`
+	Unlocked   bool    `json:"unlocked"`
+}
+
+// FishdexEntry stores a users collection data for a single fish
+type FishdexEntry struct {
+	Name        string     `json:"name"`
+	Caught      bool       `json:"caught"`
+	Count       int        `json:"count"`
+	FirstCaught *time.Time `json:"first_caught,omitempty"`
+	Largest     float64    `json:"largest"`
+}
+
+// FishdexTier stores the fishdex entries for a single tier of a location
+type FishdexTier struct {
+	Tier       int            `json:"tier"`
+	Completion float64        `json:"completion"`
+	Fish       []FishdexEntry `json:"fish"`
+}
+
+// FishdexLocation stores the fishdex tiers for a single location
+type FishdexLocation struct {
+	Location   string        `json:"location"`
+	Completion float64       `json:"completion"`
+	Tiers      []FishdexTier `json:"tiers"`
+}
+
+// FishdexData stores the data for the fishdex endpoint
+type FishdexData struct {
+	Completion float64           `json:"completion"`
+	Locations  []FishdexLocation `json:"locations"`
+	Rewards    []FishdexReward   `json:"rewards"`
+}
+
+// RecordFlags stores which records a catch has broken
+type RecordFlags struct {
+	Personal bool `json:"personal"`
+	Guild    bool `json:"guild"`
+	Global   bool `json:"global"`
+}
+
+// RecordHolder stores a single entry of a species record board
+type RecordHolder struct {
+	Rank int64   `json:"rank"`
+	User string  `json:"user"`
+	Name string  `json:"name"`
+	Size float64 `json:"size"`
+}
+
+// RecordBoard stores the record holders of a species
+type RecordBoard struct {
+	Species string         `json:"species"`
+	Holders []RecordHolder `json:"holders"`
+}
+
+// UserRecord stores a users best size for a species and their global rank for it
+type UserRecord struct {
+	Species string  `json:"species"`
+	Size    float64 `json:"size"`
+	Rank    int64   `json:"rank"`
+}
+
+// Activity stores a single player action emitted by the cast, sell, purchase and craft flows
+type Activity struct {
+	Type     string
+	UserID   string
+	GuildID  string
+	Location string
+	Fish     InvFish
+	Amount   int
+	Item     string
+	At       time.Time
+}
+
+// Rule stores a declarative goal evaluated against player activities
+type Rule struct {
+	Kind     string `json:"kind"`
+	Activity string `json:"activity"`
+	Location string `json:"location,omitempty"`
+	Tier     int    `json:"tier,omitempty"`
+	Species  string `json:"species,omitempty"`
+	Item     string `json:"item,omitempty"`
+	Target   int    `json:"target"`
+}
+
+// AchievementData holds the JSON structure for achievements.json
+type AchievementData struct {
+	Achievements []Achievement `json:"achievements"`
+}
+
+// Achievement stores a single achievement and the rule that awards it
+type Achievement struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Rule        Rule   `json:"rule"`
+	Reward      Reward `json:"reward"`
+}
+
+// AchievementStatus stores a users progress towards an achievement
+type AchievementStatus struct {
+	Achievement
+	Progress  int        `json:"progress"`
+	Awarded   bool       `json:"awarded"`
+	AwardedAt *time.Time `json:"awarded_at,omitempty"`
+}
+
+// QuestData holds the JSON structure for quests.json
+type QuestData struct {
+	Daily     QuestPeriodConfig `json:"daily"`
+	Weekly    QuestPeriodConfig `json:"weekly"`
+	Templates []QuestTemplate   `json:"templates"`
+}
+
+// QuestPeriodConfig stores how many quests are handed out each period and how many can be rerolled
+type QuestPeriodConfig struct {
+	Count   int `json:"count"`
+	Rerolls int `json:"rerolls"`
+}
+
+// QuestTemplate stores a single quest that can be handed out
+type QuestTemplate struct {
+	ID          string `json:"id"`
+	Period      string `json:"period"`
+	Description string `json:"description"`
+	Rule        Rule   `json:"rule"`
+	Reward      Reward `json:"reward"`
+}
+
+// QuestBoard stores the quests handed out to a user for a single period
+type QuestBoard struct {
+	Key     string          `json:"key"`
+	Rerolls int             `json:"rerolls"`
+	Quests  []QuestTemplate `json:"quests"`
+}
+
+// QuestStatus stores a users progress towards a quest
+type QuestStatus struct {
+	QuestTemplate
+	Progress int  `json:"progress"`
+	Goal     int  `json:"goal"`
+	Claimed  bool `json:"claimed"`
+}
+
+// QuestBoardStatus stores the data for a single period of the quests endpoint
+type QuestBoardStatus struct {
+	Period      string        `json:"period"`
+	Resets      time.Time     `json:"resets"`
+	RerollsLeft int           `json:"rerolls_left"`
+	Quests      []QuestStatus `json:"quests"`
+}
+
+// DailyConfig holds the JSON structure for daily.json
+type DailyConfig struct {
+	IntervalHours int      `json:"interval_hours"`
+	GraceHours    int      `json:"grace_hours"`
+	Rewards       []Reward `json:"rewards"`
+}
+
+// DailyStatus stores a users daily reward streak
+type DailyStatus struct {
+	Streak       int       `json:"streak"`
+	NextClaim    time.Time `json:"next_claim"`
+	StreakExpiry time.Time `json:"streak_expiry"`
+	Reward       *Reward   `json:"reward,omitempty"`
+}
+
+// Tournament stores a guild fishing tournament
+type Tournament struct {
+	ID        string    `json:"id"`
+	GuildID   string    `json:"guild_id"`
+	Name      string    `json:"name"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Duration  int       `json:"duration,omitempty"`
+	Scoring   string    `json:"scoring"`
+	Species   string    `json:"species,omitempty"`
+	Locations []string  `json:"locations"`
+	Prizes    []Reward  `json:"prizes"`
+}
+
+// TournamentStanding stores a single entry of a tournaments standings
+type TournamentStanding struct {
+	Rank  int64   `json:"rank"`
+	User  string  `json:"user"`
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+	Prize *Reward `json:"prize,omitempty"`
+}
+
+// TournamentResults stores the standings of a running or finished tournament
+type TournamentResults struct {
+	Tournament Tournament           `json:"tournament"`
+	Ended      bool                 `json:"ended"`
+	Standings  []TournamentStanding `json:"standings"`
+}
+
+// TradeSide stores what one party of a trade hands over
+type TradeSide struct {
+	Fish []string      `json:"fish"`
+	Bait []BaitRequest `json:"bait"`
+	Yen  int           `json:"yen"`
+}
+
+// TradeOffer stores a trade proposed by one user to another
+type TradeOffer struct {
+	ID      string    `json:"id"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Offer   TradeSide `json:"offer"`
+	Request TradeSide `json:"request"`
+	Status  string    `json:"status"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+}
+
+// TradeList stores the data for the trades endpoint
+type TradeList struct {
+	Incoming []TradeOffer `json:"incoming"`
+	Outgoing []TradeOffer `json:"outgoing"`
+	History  []TradeOffer `json:"history"`
+}
+
+// Auction stores a fish listed on the auction house, the high bid is held in escrow until it settles
+type Auction struct {
+	ID         string    `json:"id"`
+	Seller     string    `json:"seller"`
+	FishID     string    `json:"fish_id,omitempty"`
+	Fish       InvFish   `json:"fish"`
+	Reserve    int       `json:"reserve"`
+	Duration   int       `json:"duration,omitempty"`
+	HighBid    int       `json:"high_bid"`
+	HighBidder string    `json:"high_bidder,omitempty"`
+	Status     string    `json:"status"`
+	Created    time.Time `json:"created"`
+	Ends       time.Time `json:"ends"`
+}
+
+// AuctionBid stores the data for the bid endpoint
+type AuctionBid struct {
+	Amount int `json:"amount"`
+}
+
+// Aquarium stores the fish a user is showing off and what they are worth
+type Aquarium struct {
+	Fish   []InvFish `json:"fish"`
+	Slots  int       `json:"slots"`
+	Value  float64   `json:"value"`
+	Rarity int       `json:"rarity"`
+}
+
+// AquariumMove stores the data for moving fish in and out of an aquarium
+type AquariumMove struct {
+	Fish []string `json:"fish"`
+}
+
+// AquariumRank stores a single entry of an aquarium leaderboard
+type AquariumRank struct {
+	Rank  int64   `json:"rank"`
+	User  string  `json:"user"`
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// RecipeData holds the JSON structure for recipes.json
+type RecipeData struct {
+	Consumables []Consumable `json:"consumables"`
+	Recipes     []Recipe     `json:"recipes"`
+}
+
+// Consumable stores an item that can only be made by crafting
+type Consumable struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Buffs       []BuffGrant `json:"buffs"`
+}
+
+// Recipe stores the ingredients a recipe uses up and what it makes
+type Recipe struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Fish        []RecipeFish `json:"fish"`
+	Garbage     int          `json:"garbage"`
+	Output      RecipeOutput `json:"output"`
+}
+
+// RecipeFish stores a fish ingredient of a recipe, empty fields match any fish
+type RecipeFish struct {
+	Species  string `json:"species,omitempty"`
+	Tier     int    `json:"tier,omitempty"`
+	Location string `json:"location,omitempty"`
+	Amount   int    `json:"amount"`
+}
+
+// RecipeOutput stores what crafting a recipe gives
+type RecipeOutput struct {
+	Bait        []BaitRequest  `json:"bait,omitempty"`
+	Consumables map[string]int `json:"consumables,omitempty"`
+}
+
+// CraftResult stores the data for the craft endpoint
+type CraftResult struct {
+	Recipe  Recipe    `json:"recipe"`
+	Used    []InvFish `json:"used"`
+	Garbage int       `json:"garbage"`
+}
+
+// Durability stores how worn an equipped item is, items without a max durability never wear out
+type Durability struct {
+	Item      int  `json:"item"`
+	Remaining int  `json:"remaining"`
+	Max       int  `json:"max"`
+	Broken    bool `json:"broken"`
+}
+
+// BuffGrant stores a buff handed out by an item, event or reward, duration is in minutes
+type BuffGrant struct {
+	Kind     string  `json:"kind"`
+	Value    float64 `json:"value"`
+	Duration int     `json:"duration,omitempty"`
+}
+
+// Buff stores a time-limited effect active on a user
+type Buff struct {
+	Kind      string    `json:"kind"`
+	Value     float64   `json:"value"`
+	Source    string    `json:"source"`
+	Expires   time.Time `json:"expires"`
+	Remaining int64     `json:"remaining"`
+}
+
+// PrestigeConfig holds the JSON structure for prestige.json, bonuses are added per prestige level
+type PrestigeConfig struct {
+	MinExp   float64  `json:"min_exp"`
+	ExpBonus float64  `json:"exp_bonus"`
+	YenBonus float64  `json:"yen_bonus"`
+	Badges   []string `json:"badges"`
+}
+
+// PrestigeRecord stores a single prestige in a users history
+type PrestigeRecord struct {
+	Level int       `json:"level"`
+	Exp   float64   `json:"exp"`
+	Time  time.Time `json:"time"`
+}
+
+// PrestigeStatus stores the data for the prestige endpoint
+type PrestigeStatus struct {
+	Level         int              `json:"level"`
+	Badge         string           `json:"badge"`
+	ExpMultiplier float64          `json:"exp_multiplier"`
+	YenMultiplier float64          `json:"yen_multiplier"`
+	Required      float64          `json:"required"`
+	Eligible      bool             `json:"eligible"`
+	History       []PrestigeRecord `json:"history"`
+}
+
+// ReelConfig holds the JSON structure for reel.json, windows are in milliseconds for each fish tier
+type ReelConfig struct {
+	Windows []int        `json:"windows"`
+	Expiry  int          `json:"expiry"`
+	Actions []ReelAction `json:"actions"`
+}
+
+// ReelAction stores an action a player can be asked to make and the cue that asks for it
+type ReelAction struct {
+	Action string `json:"action"`
+	Cue    string `json:"cue"`
+}
+
+// ReelSession stores a cast waiting for the player to react to a bite
+type ReelSession struct {
+	Token    string         `json:"token"`
+	UserID   string         `json:"user_id"`
+	Username string         `json:"username"`
+	GuildID  string         `json:"guild_id"`
+	Location string         `json:"location"`
+	Density  UserLocDensity `json:"density"`
+	Bite     int64          `json:"bite"`
+	Catch    int64          `json:"catch"`
+	Fish     int64          `json:"fish"`
+	Tier     int            `json:"tier"`
+	Action   string         `json:"action"`
+	Window   int            `json:"window"`
+	Started  time.Time      `json:"started"`
+	Party    string         `json:"party,omitempty"`
+}
+
+// ReelBite stores the data for the bite phase of a reel in
+type ReelBite struct {
+	Token   string    `json:"token"`
+	Cue     string    `json:"cue"`
+	Window  int       `json:"window"`
+	Expires time.Time `json:"expires"`
+}
+
+// ReelRequest stores the data for the catch phase of a reel in
+type ReelRequest struct {
+	Token  string `json:"token"`
+	Action string `json:"action"`
+}
+
+// BatchRequest stores the data for a batch of casts made with an auto-fisher
+type BatchRequest struct {
+	Message *discordgo.Message `json:"message"`
+	Casts   int                `json:"casts"`
+}
+
+// BatchCast stores the outcome of a single cast in a batch, a failed cast has the outcome bite or catch
+type BatchCast struct {
+	Outcome string   `json:"outcome"`
+	Fish    *InvFish `json:"fish,omitempty"`
+}
+
+// BatchResult stores the totals and the individual outcomes of a batch of casts
+type BatchResult struct {
+	Casts    int         `json:"casts"`
+	Fish     int         `json:"fish"`
+	Garbage  int         `json:"garbage"`
+	Fails    int         `json:"fails"`
+	Worth    float64     `json:"worth"`
+	Exp      float64     `json:"exp"`
+	BaitUsed int         `json:"bait_used"`
+	Cooldown int64       `json:"cooldown"`
+	Stopped  string      `json:"stopped,omitempty"`
+	Luck     Luck        `json:"luck"`
+	Results  []BatchCast `json:"results"`
+	Notified []string    `json:"notified,omitempty"`
+}
+
+// NetConfig holds the JSON structure for nets.json, a deployed net makes a cast every interval minutes
+type NetConfig struct {
+	Interval    int `json:"interval"`
+	MinDuration int `json:"min_duration"`
+	MaxDuration int `json:"max_duration"`
+}
+
+// NetRequest stores the data for deploying a net, the duration is in minutes
+type NetRequest struct {
+	GuildID  string `json:"guildid"`
+	Location string `json:"location"`
+	Duration int    `json:"duration"`
+}
+
+// Net stores a net a user has deployed
+type Net struct {
+	GuildID  string    `json:"guildid"`
+	Location string    `json:"location"`
+	Deployed time.Time `json:"deployed"`
+	Ends     time.Time `json:"ends"`
+}
+
+// NetYield stores what a deployed net is expected to have caught so far with a users current gear
+type NetYield struct {
+	Net
+	Casts   int     `json:"casts"`
+	Fish    float64 `json:"fish"`
+	Garbage float64 `json:"garbage"`
+	Space   int     `json:"space"`
+	Done    bool    `json:"done"`
+}
+
+// PartyConfig holds the JSON structure for party.json, the duration is in minutes
+// and catches of the rare tier or higher are shared with the rest of the party
+type PartyConfig struct {
+	BiteBonus  int64 `json:"bite_bonus"`
+	MaxMembers int   `json:"max_members"`
+	Duration   int   `json:"duration"`
+	RareTier   int   `json:"rare_tier"`
+}
+
+// Party stores a group of users from the same guild that fish together
+type Party struct {
+	ID      string    `json:"id"`
+	GuildID string    `json:"guild_id"`
+	Leader  string    `json:"leader"`
+	Members []string  `json:"members"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+}
+
+// PartyCatch stores a rare catch shared with a party
+type PartyCatch struct {
+	UserID string    `json:"user_id"`
+	Fish   InvFish   `json:"fish"`
+	Caught time.Time `json:"caught"`
+}
+
+// PartyStatus stores a party with its current bite bonus and recent rare catches
+type PartyStatus struct {
+	Party
+	Bonus   int64        `json:"bonus"`
+	Catches []PartyCatch `json:"catches"`
+}
+
+// GiftConfig holds the JSON structure for gifts.json, the daily limits on how much a user can send and receive
+type GiftConfig struct {
+	SendBait    int `json:"send_bait"`
+	SendYen     int `json:"send_yen"`
+	ReceiveBait int `json:"receive_bait"`
+	ReceiveYen  int `json:"receive_yen"`
+}
+
+// Gift stores bait of a tier or yen sent from one user to another
+type Gift struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	Tier int       `json:"tier,omitempty"`
+	Bait int       `json:"bait,omitempty"`
+	Yen  int       `json:"yen,omitempty"`
+	Sent time.Time `json:"sent"`
+}
+
+// ClubConfig holds the JSON structure for clubs.json
+type ClubConfig struct {
+	Levels []ClubLevel `json:"levels"`
+	Goals  []ClubGoal  `json:"goals"`
+}
+
+// ClubLevel is unlocked once a clubs pool reaches its exp, its bonuses apply to every member casting in the guild
+type ClubLevel struct {
+	Exp      float64 `json:"exp"`
+	Bite     int64   `json:"bite"`
+	Catch    int64   `json:"catch"`
+	ExpBonus float64 `json:"exp_bonus"`
+}
+
+// ClubGoal is a target for the exp a club earns in a week, reaching it adds the reward to the clubs pool
+type ClubGoal struct {
+	Name   string  `json:"name"`
+	Target float64 `json:"target"`
+	Reward float64 `json:"reward"`
+}
+
+// ClubGoalStatus stores a weekly goal and whether a club has reached it
+type ClubGoalStatus struct {
+	ClubGoal
+	Completed bool `json:"completed"`
+}
+
+// ClubMember stores how much exp a member has contributed to their club
+type ClubMember struct {
+	Rank int64   `json:"rank"`
+	User string  `json:"user"`
+	Name string  `json:"name"`
+	Exp  float64 `json:"exp"`
+}
+
+// ClubWeek stores a clubs progress towards its goals for the current week
+type ClubWeek struct {
+	Exp   float64          `json:"exp"`
+	Ends  time.Time        `json:"ends"`
+	Goals []ClubGoalStatus `json:"goals"`
+	Top   []ClubMember     `json:"top"`
+}
+
+// Club stores the profile of a guilds fishing club
+type Club struct {
+	GuildID     string       `json:"guild_id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Exp         float64      `json:"exp"`
+	Level       int          `json:"level"`
+	Perks       ClubLevel    `json:"perks"`
+	NextLevel   float64      `json:"next_level,omitempty"`
+	Week        ClubWeek     `json:"week"`
+	Top         []ClubMember `json:"top"`
+}
+
+// ClubRequest stores the data for editing a clubs profile
+type ClubRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// LuckConfig holds the JSON structure for luck.json,
+// pity is added to the bite and catch rates per failed cast and streak exp is added per fish caught in a row
+type LuckConfig struct {
+	PityStep  int64   `json:"pity_step"`
+	PityMax   int64   `json:"pity_max"`
+	StreakExp float64 `json:"streak_exp"`
+	StreakMax float64 `json:"streak_max"`
+}
+
+// Luck stores a users run of failed casts and their streak of fish caught in a row
+type Luck struct {
+	Fails  int `json:"fails"`
+	Streak int `json:"streak"`
+}
+
+// MarketConfig holds the JSON structure for market.json
+type MarketConfig struct {
+	Min             float64 `json:"min"`
+	Max             float64 `json:"max"`
+	SaleImpact      float64 `json:"sale_impact"`
+	Recovery        float64 `json:"recovery"`
+	Drift           float64 `json:"drift"`
+	IntervalMinutes int     `json:"interval_minutes"`
+}
+
+// MarketPrice stores the live sell price of a species
+type MarketPrice struct {
+	Species    string  `json:"species"`
+	Tier       int     `json:"tier"`
+	Multiplier float64 `json:"multiplier"`
+	MinPrice   float64 `json:"min_price"`
+	MaxPrice   float64 `json:"max_price"`
+	Trend      string  `json:"trend"`
+}
+
 var (
 	Fish    FishData
 	Trash   TrashData
@@ -260,6 +947,34 @@ var (
 	Config  ConfigData
 	Levels  LevelData
 	Secrets SecretStrings
+	Events  EventData
+	Dex     FishdexConfig
+	Achieve AchievementData
+	Recipes RecipeData
+	Clubs   ClubConfig
+
+	// the settings of features below are used as they are when their file is missing,
+	// a file only has to set what it changes
+	Quests = QuestData{
+		Daily:  QuestPeriodConfig{Count: 3, Rerolls: 1},
+		Weekly: QuestPeriodConfig{Count: 2, Rerolls: 1},
+	}
+	Daily    = DailyConfig{IntervalHours: 24, GraceHours: 24}
+	Market   = MarketConfig{Min: 0.5, Max: 1.5, SaleImpact: 0.01, Recovery: 0.1, Drift: 0.02, IntervalMinutes: 10}
+	Prestige = PrestigeConfig{MinExp: 1000, ExpBonus: 0.1, YenBonus: 0.05}
+	Reel     = ReelConfig{
+		Windows: []int{3000, 2500, 2000, 1500, 1000},
+		Expiry:  30,
+		Actions: []ReelAction{
+			{Action: "reel", Cue: "Your line goes tight, reel it in!"},
+			{Action: "pull", Cue: "The fish is diving, pull back!"},
+			{Action: "slack", Cue: "Your line is about to snap, give it some slack!"},
+		},
+	}
+	LuckConf = LuckConfig{PityStep: 5, PityMax: 30, StreakExp: 0.05, StreakMax: 0.5}
+	Nets     = NetConfig{Interval: 10, MinDuration: 30, MaxDuration: 720}
+	Parties  = PartyConfig{BiteBonus: 5, MaxMembers: 5, Duration: 120, RareTier: 4}
+	Gifts    = GiftConfig{SendBait: 25, SendYen: 2500, ReceiveBait: 50, ReceiveYen: 5000}
 
 	files = map[string]interface{}{
 		"json/fish.json":          &Fish,
@@ -269,6 +984,25 @@ var (
 		"json/secretstrings.json": &Secrets,
 		"json/trash.json":         &Trash,
 	}
+
+	// featureFiles are optional, a missing file leaves the settings above as they are
+	// and leaves the events, fishdex rewards, achievements, quests, daily rewards, recipes and club levels it would list empty
+	featureFiles = map[string]interface{}{
+		"json/events.json":       &Events,
+		"json/fishdex.json":      &Dex,
+		"json/achievements.json": &Achieve,
+		"json/quests.json":       &Quests,
+		"json/daily.json":        &Daily,
+		"json/market.json":       &Market,
+		"json/recipes.json":      &Recipes,
+		"json/prestige.json":     &Prestige,
+		"json/reel.json":         &Reel,
+		"json/luck.json":         &LuckConf,
+		"json/nets.json":         &Nets,
+		"json/party.json":        &Parties,
+		"json/gifts.json":        &Gifts,
+		"json/clubs.json":        &Clubs,
+	}
 )
 
 func GetConfigs() {
@@ -278,6 +1012,20 @@ func GetConfigs() {
 			log.Panic(k + " not detected in current directory, " + err.Error())
 		}
 
+		if err := json.Unmarshal(data, &v); err != nil {
+			log.Panic("Could not unmarshal json file " + k + ", " + err.Error())
+		}
+	}
+	for k, v := range featureFiles {
+		data, err := ioutil.ReadFile(k)
+		if os.IsNotExist(err) {
+			log.Println(k + " not detected in current directory, using the built-in settings without any listed content")
+			continue
+		}
+		if err != nil {
+			log.Panic("Could not read json file " + k + ", " + err.Error())
+		}
+
 		if err := json.Unmarshal(data, &v); err != nil {
 			log.Panic("Could not unmarshal json file " + k + ", " + err.Error())
 		}
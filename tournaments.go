@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+	log "github.com/sirupsen/logrus"
+)
+
+var tournamentScoring = map[string]bool{
+	"fish":    true,
+	"biggest": true,
+	"worth":   true,
+	"species": true,
+}
+
+func init() {
+	onActivity(tournamentActivity)
+}
+
+// tournamentActivity submits a catch to every tournament of the guild it was made in that was running when it was caught
+func tournamentActivity(a Activity) {
+	if a.Type != "fish" || a.GuildID == "" {
+		return
+	}
+	for _, t := range DBGetGuildTournaments(a.GuildID) {
+		if !t.running(a.At) || !t.allows(a.Location) {
+			continue
+		}
+		key := StandingsKey(t.ID)
+		var err error
+		switch t.Scoring {
+		case "fish":
+			err = redisClient.ZIncrBy(key, 1, a.UserID).Err()
+		case "worth":
+			err = redisClient.ZIncrBy(key, a.Fish.Price, a.UserID).Err()
+		case "biggest":
+			_, err = zaddMax(key, a.UserID, a.Fish.Size)
+		case "species":
+			if a.Fish.Name == t.Species {
+				err = redisClient.ZIncrBy(key, 1, a.UserID).Err()
+			}
+		}
+		if err != nil {
+			logError("Unable to submit tournament entry", err)
+		}
+	}
+}
+
+func (t Tournament) running(now time.Time) bool {
+	return !now.Before(t.Start) && now.Before(t.End)
+}
+
+func (t Tournament) allows(location string) bool {
+	if len(t.Locations) == 0 {
+		return true
+	}
+	for _, l := range t.Locations {
+		if l == location {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Tournament) validate() error {
+	if t.Name == "" {
+		return errors.New("Tournament name is required")
+	}
+	if !tournamentScoring[t.Scoring] {
+		return fmt.Errorf("Invalid scoring rule %s", t.Scoring)
+	}
+	if t.Scoring == "species" && t.Species == "" {
+		return errors.New("A species is required for species tournaments")
+	}
+	if !t.End.After(t.Start) {
+		return errors.New("Tournament must end after it starts")
+	}
+	for _, l := range t.Locations {
+		if !validLocations[l] {
+			return fmt.Errorf("Invalid location %s", l)
+		}
+	}
+	return nil
+}
+
+// DBCreateTournament stores a new tournament and returns it with its assigned id
+func DBCreateTournament(t Tournament) (Tournament, error) {
+	id, err := redisClient.Incr(TourneyIDKey).Result()
+	if err != nil {
+		return Tournament{}, err
+	}
+	t.ID = strconv.FormatInt(id, 10)
+	data, err := json.Marshal(t)
+	if err != nil {
+		return Tournament{}, err
+	}
+	_, err = redisClient.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.HSet(TournamentsKey, t.ID, data)
+		pipe.SAdd(GuildTourneys(t.GuildID), t.ID)
+		pipe.ZAdd(TourneyEndKey, redis.Z{Score: float64(t.End.Unix()), Member: t.ID})
+		return nil
+	})
+	return t, err
+}
+
+// DBGetTournament returns a single tournament
+func DBGetTournament(tournamentID string) (Tournament, error) {
+	var t Tournament
+	data, err := redisClient.HGet(TournamentsKey, tournamentID).Result()
+	if err != nil {
+		return t, err
+	}
+	err = json.Unmarshal([]byte(data), &t)
+	return t, err
+}
+
+// DBGetGuildTournaments returns every tournament of a guild that hasn't been finished yet,
+// including those that haven't started and those that have ended but are waiting to be finished
+func DBGetGuildTournaments(guildID string) []Tournament {
+	tournaments := []Tournament{}
+	for _, id := range redisClient.SMembers(GuildTourneys(guildID)).Val() {
+		t, err := DBGetTournament(id)
+		if err != nil {
+			logError("Unable to retrieve tournament", err)
+			continue
+		}
+		tournaments = append(tournaments, t)
+	}
+	return tournaments
+}
+
+// DBGetStandings returns the top standings of a tournament with the prize each placing wins
+func DBGetStandings(t Tournament) ([]TournamentStanding, error) {
+	standings := []TournamentStanding{}
+	n := 10
+	if len(t.Prizes) > n {
+		n = len(t.Prizes)
+	}
+	z, err := redisClient.ZRevRangeWithScores(StandingsKey(t.ID), 0, int64(n-1)).Result()
+	if err != nil {
+		return standings, err
+	}
+	for i, e := range z {
+		user := e.Member.(string)
+		s := TournamentStanding{Rank: int64(i + 1), User: user, Name: DBGetTrackedUser(user), Score: e.Score}
+		if i < len(t.Prizes) {
+			s.Prize = &t.Prizes[i]
+		}
+		standings = append(standings, s)
+	}
+	return standings, nil
+}
+
+// DBGetTournamentResults returns the archived results of a finished tournament
+func DBGetTournamentResults(tournamentID string) (TournamentResults, error) {
+	var res TournamentResults
+	data, err := redisClient.Get(TourneyResKey(tournamentID)).Result()
+	if err != nil {
+		return res, err
+	}
+	err = json.Unmarshal([]byte(data), &res)
+	return res, err
+}
+
+// DBGetTournamentArchive returns the results of the last 10 finished tournaments of a guild
+func DBGetTournamentArchive(guildID string) []TournamentResults {
+	archive := []TournamentResults{}
+	for _, id := range redisClient.LRange(TourneyArchKey(guildID), 0, 9).Val() {
+		res, err := DBGetTournamentResults(id)
+		if err != nil {
+			logError("Unable to retrieve tournament results", err)
+			continue
+		}
+		archive = append(archive, res)
+	}
+	return archive
+}
+
+// endTournaments finishes every tournament whose end time has passed,
+// a tournament that could not be archived is put back to be retried
+func endTournaments() {
+	ended, err := redisClient.ZRangeByScoreWithScores(TourneyEndKey, redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(CurrentTime.Unix(), 10),
+	}).Result()
+	if err != nil {
+		logError("Unable to retrieve ended tournaments", err)
+		return
+	}
+	for _, z := range ended {
+		id := z.Member.(string)
+		// only the instance that removes the tournament from the set finishes it
+		if redisClient.ZRem(TourneyEndKey, id).Val() == 0 {
+			continue
+		}
+		if err := finishTournament(id); err != nil {
+			logError("Unable to finish tournament", err)
+			redisClient.ZAdd(TourneyEndKey, z)
+		}
+	}
+}
+
+// finishTournament archives a tournaments final standings and then pays out its prizes,
+// nothing is paid out when it returns an error
+func finishTournament(tournamentID string) error {
+	t, err := DBGetTournament(tournamentID)
+	if err != nil {
+		return err
+	}
+	standings, err := DBGetStandings(t)
+	if err != nil {
+		return err
+	}
+	if err := marshalAndSet(TournamentResults{t, true, standings}, TourneyResKey(t.ID), 0); err != nil {
+		return err
+	}
+	for _, s := range standings {
+		if s.Prize == nil {
+			continue
+		}
+		if err := DBGiveReward(s.User, *s.Prize); err != nil {
+			logError("Unable to give tournament prize", err)
+		}
+	}
+	redisClient.LPush(TourneyArchKey(t.GuildID), t.ID)
+	redisClient.SRem(GuildTourneys(t.GuildID), t.ID)
+	redisClient.Del(StandingsKey(t.ID))
+	log.WithFields(log.Fields{
+		"tournament": t.ID,
+		"guild":      t.GuildID,
+		"entries":    len(standings),
+	}).Debug("tournament-ended")
+	return nil
+}
@@ -8,15 +8,6 @@ import (
 )
 
 func init() {
-	redisClient = redis.NewClient(&redis.Options{
-		Addr:     Config.Redis.URL,
-		Password: Config.Redis.Password,
-		DB:       Config.Redis.DB,
-	})
-	if err := redisClient.Ping().Err(); err != nil {
-		panic(err)
-	}
-
 	p := time.Tick(1 * time.Minute)
 
 	go func() {
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis"
+	log "github.com/sirupsen/logrus"
+)
+
+func (s TradeSide) empty() bool {
+	return len(s.Fish) == 0 && len(s.Bait) == 0 && s.Yen == 0
+}
+
+func (s TradeSide) validate() error {
+	if s.Yen < 0 {
+		return errors.New("Yen cannot be negative")
+	}
+	seen := map[string]bool{}
+	for _, id := range s.Fish {
+		if seen[id] {
+			return fmt.Errorf("Fish %s is listed more than once", id)
+		}
+		seen[id] = true
+	}
+	for _, b := range s.Bait {
+		if b.Tier < 1 || b.Tier > 5 {
+			return fmt.Errorf("Invalid bait tier %v", b.Tier)
+		}
+		if b.Amount < 1 {
+			return errors.New("Bait amounts must be positive")
+		}
+	}
+	return nil
+}
+
+func (t TradeOffer) validate() error {
+	if t.To == "" {
+		return errors.New("Trade recipient is required")
+	}
+	if t.From == t.To {
+		return errors.New("You cannot trade with yourself")
+	}
+	if t.Offer.empty() && t.Request.empty() {
+		return errors.New("Trade is empty")
+	}
+	if err := t.Offer.validate(); err != nil {
+		return err
+	}
+	return t.Request.validate()
+}
+
+// tradeFish returns the fish a side of a trade hands over, making sure the giver owns all of them
+func tradeFish(c redis.Cmdable, giver string, s TradeSide) ([]InvFish, error) {
+	return fishByID(c, giver, s.Fish)
+}
+
+// checkTradeSide makes sure a giver owns the bait and yen a side of a trade hands over
+func checkTradeSide(c redis.Cmdable, giver string, s TradeSide) error {
+	for _, b := range s.Bait {
+		if baitAmount(c, giver, b.Tier) < b.Amount {
+			return fmt.Errorf("%s does not have %v tier %v bait", DBGetTrackedUser(giver), b.Amount, b.Tier)
+		}
+	}
+	if walletBalance(c, giver) < s.Yen {
+		return fmt.Errorf("%s does not have %v yen", DBGetTrackedUser(giver), s.Yen)
+	}
+	return nil
+}
+
+// checkTradeRoom makes sure a receiver can carry the fish and bait they end up with after a trade
+func checkTradeRoom(c redis.Cmdable, receiver string, in, out TradeSide) error {
+	if invSize(c, receiver)+len(in.Fish)-len(out.Fish) > DBGetInvCapacity(receiver) {
+		return fmt.Errorf("%s does not have enough room for the fish", DBGetTrackedUser(receiver))
+	}
+	net := map[int]int{}
+	for _, b := range in.Bait {
+		net[b.Tier] += b.Amount
+	}
+	for _, b := range out.Bait {
+		net[b.Tier] -= b.Amount
+	}
+	cap := DBGetBaitCapacity(receiver)
+	for tier, n := range net {
+		if n > 0 && baitAmount(c, receiver, tier)+n > cap {
+			return fmt.Errorf("%s does not have enough room for the tier %v bait", DBGetTrackedUser(receiver), tier)
+		}
+	}
+	return nil
+}
+
+// applyTradeSide queues the transfer of one side of a trade from the giver to the receiver
+func applyTradeSide(pipe redis.Pipeliner, giver, receiver string, s TradeSide, fish []InvFish) {
+	queueRemoveFish(pipe, giver, fish)
+	queueAddFish(pipe, receiver, fish)
+	for _, b := range s.Bait {
+		pipe.HIncrBy(BaitInvKey(giver), strconv.Itoa(b.Tier), int64(-b.Amount))
+		pipe.HIncrBy(BaitInvKey(receiver), strconv.Itoa(b.Tier), int64(b.Amount))
+	}
+	if s.Yen > 0 {
+		pipe.IncrBy(WalletKey(giver), int64(-s.Yen))
+		pipe.IncrBy(WalletKey(receiver), int64(s.Yen))
+	}
+}
+
+// closeTrade queues the removal of a trade from the pending lists and adds it to both users histories
+func closeTrade(pipe redis.Pipeliner, t TradeOffer) {
+	data, _ := json.Marshal(t)
+	pipe.Set(TradeKey(t.ID), data, 0)
+	pipe.SRem(TradesInKey(t.To), t.ID)
+	pipe.SRem(TradesOutKey(t.From), t.ID)
+	pipe.ZRem(TradeExpireKey, t.ID)
+	for _, u := range []string{t.From, t.To} {
+		pipe.LPush(TradeHistKey(u), t.ID)
+		pipe.LTrim(TradeHistKey(u), 0, 49)
+	}
+}
+
+func getTrade(c redis.Cmdable, tradeID string) (TradeOffer, error) {
+	var t TradeOffer
+	data, err := c.Get(TradeKey(tradeID)).Result()
+	if err == redis.Nil {
+		return t, fmt.Errorf("Trade %s does not exist", tradeID)
+	}
+	if err != nil {
+		return t, err
+	}
+	err = json.Unmarshal([]byte(data), &t)
+	return t, err
+}
+
+// DBCreateTrade stores a new trade offer and returns it with its assigned id
+func DBCreateTrade(t TradeOffer) (TradeOffer, error) {
+	if _, err := tradeFish(redisClient, t.From, t.Offer); err != nil {
+		return TradeOffer{}, err
+	}
+	if _, err := tradeFish(redisClient, t.To, t.Request); err != nil {
+		return TradeOffer{}, err
+	}
+	if err := checkTradeSide(redisClient, t.From, t.Offer); err != nil {
+		return TradeOffer{}, err
+	}
+	id, err := redisClient.Incr(TradeIDKey).Result()
+	if err != nil {
+		return TradeOffer{}, err
+	}
+	t.ID = strconv.FormatInt(id, 10)
+	t.Status = "pending"
+	t.Created = CurrentTime
+	t.Expires = CurrentTime.Add(TradeTimeout)
+	data, err := json.Marshal(t)
+	if err != nil {
+		return TradeOffer{}, err
+	}
+	_, err = redisClient.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Set(TradeKey(t.ID), data, 0)
+		pipe.SAdd(TradesInKey(t.To), t.ID)
+		pipe.SAdd(TradesOutKey(t.From), t.ID)
+		pipe.ZAdd(TradeExpireKey, redis.Z{Score: float64(t.Expires.Unix()), Member: t.ID})
+		return nil
+	})
+	return t, err
+}
+
+// DBAcceptTrade atomically exchanges everything in a trade, failing if either side no longer owns what they offered
+func DBAcceptTrade(userID, tradeID string) (TradeOffer, error) {
+	t, err := getTrade(redisClient, tradeID)
+	if err != nil {
+		return t, err
+	}
+	keys := []string{TradeKey(t.ID)}
+	for _, u := range []string{t.From, t.To} {
+		keys = append(keys, CatchesKey(u), FishInvKey(u), BaitInvKey(u), WalletKey(u))
+	}
+	err = watchTx(func(tx *redis.Tx) error {
+		t, err = getTrade(tx, tradeID)
+		if err != nil {
+			return err
+		}
+		if t.To != userID {
+			return errors.New("Only the recipient can accept a trade")
+		}
+		if t.Status != "pending" {
+			return fmt.Errorf("Trade is already %s", t.Status)
+		}
+		if !CurrentTime.Before(t.Expires) {
+			return errors.New("Trade has expired")
+		}
+		give, err := tradeFish(tx, t.From, t.Offer)
+		if err != nil {
+			return err
+		}
+		take, err := tradeFish(tx, t.To, t.Request)
+		if err != nil {
+			return err
+		}
+		if err := checkTradeSide(tx, t.From, t.Offer); err != nil {
+			return err
+		}
+		if err := checkTradeSide(tx, t.To, t.Request); err != nil {
+			return err
+		}
+		if err := checkTradeRoom(tx, t.To, t.Offer, t.Request); err != nil {
+			return err
+		}
+		if err := checkTradeRoom(tx, t.From, t.Request, t.Offer); err != nil {
+			return err
+		}
+		t.Status = "accepted"
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			applyTradeSide(pipe, t.From, t.To, t.Offer, give)
+			applyTradeSide(pipe, t.To, t.From, t.Request, take)
+			closeTrade(pipe, t)
+			return nil
+		})
+		return err
+	}, keys...)
+	return t, err
+}
+
+// DBGetTrade returns a single trade
+func DBGetTrade(tradeID string) (TradeOffer, error) {
+	return getTrade(redisClient, tradeID)
+}
+
+// DBCloseTrade closes a pending trade without exchanging anything,
+// status is called with the trade before it is closed and returns its new status or rejects the change
+func DBCloseTrade(tradeID string, status func(TradeOffer) (string, error)) (TradeOffer, error) {
+	var t TradeOffer
+	err := watchTx(func(tx *redis.Tx) error {
+		var err error
+		t, err = getTrade(tx, tradeID)
+		if err != nil {
+			return err
+		}
+		if t.Status != "pending" {
+			return fmt.Errorf("Trade is already %s", t.Status)
+		}
+		if t.Status, err = status(t); err != nil {
+			return err
+		}
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			closeTrade(pipe, t)
+			return nil
+		})
+		return err
+	}, TradeKey(tradeID))
+	return t, err
+}
+
+// DBGetTrades returns a users pending trades and their trade history
+func DBGetTrades(userID string) TradeList {
+	get := func(ids []string) []TradeOffer {
+		trades := []TradeOffer{}
+		for _, id := range ids {
+			t, err := getTrade(redisClient, id)
+			if err != nil {
+				logError("Unable to retrieve trade", err)
+				continue
+			}
+			trades = append(trades, t)
+		}
+		return trades
+	}
+	return TradeList{
+		get(redisClient.SMembers(TradesInKey(userID)).Val()),
+		get(redisClient.SMembers(TradesOutKey(userID)).Val()),
+		get(redisClient.LRange(TradeHistKey(userID), 0, 24).Val()),
+	}
+}
+
+// expireTrades closes every pending trade that has gone stale
+func expireTrades() {
+	ids, err := redisClient.ZRangeByScore(TradeExpireKey, redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(CurrentTime.Unix(), 10),
+	}).Result()
+	if err != nil {
+		logError("Unable to retrieve expired trades", err)
+		return
+	}
+	for _, id := range ids {
+		t, err := DBCloseTrade(id, func(TradeOffer) (string, error) { return "expired", nil })
+		if err != nil {
+			redisClient.ZRem(TradeExpireKey, id)
+			continue
+		}
+		log.WithFields(log.Fields{
+			"trade": t.ID,
+			"from":  t.From,
+			"to":    t.To,
+		}).Debug("trade-expired")
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import "testing"
+
+func TestAcceptTradeExchangesBothSides(t *testing.T) {
+	newTestRedis(t)
+	fish := giveTestFish(t, "alice", InvFish{Name: "Salmon", Price: 40, Tier: 2})
+	DBAddWallet("alice", 100)
+	redisClient.HSet(BaitInvKey("bob"), "2", 10)
+
+	trade, err := DBCreateTrade(TradeOffer{
+		From:    "alice",
+		To:      "bob",
+		Offer:   TradeSide{Fish: []string{fish.ID}, Yen: 60},
+		Request: TradeSide{Bait: []BaitRequest{{Tier: 2, Amount: 4}}},
+	})
+	if err != nil {
+		t.Fatalf("creating trade: %v", err)
+	}
+	trade, err = DBAcceptTrade("bob", trade.ID)
+	if err != nil {
+		t.Fatalf("accepting trade: %v", err)
+	}
+	if trade.Status != "accepted" {
+		t.Errorf("status = %q, want accepted", trade.Status)
+	}
+
+	if hasFish("alice", fish.ID) || !hasFish("bob", fish.ID) {
+		t.Errorf("fish %s was not moved from alice to bob", fish.ID)
+	}
+	if n, worth := fishCount("alice"); n != 0 || worth != 0 {
+		t.Errorf("alice inventory = %v fish worth %v, want empty", n, worth)
+	}
+	if n, worth := fishCount("bob"); n != 1 || worth != 40 {
+		t.Errorf("bob inventory = %v fish worth %v, want 1 worth 40", n, worth)
+	}
+	if got := DBGetWallet("alice"); got != 40 {
+		t.Errorf("alice wallet = %v, want 40", got)
+	}
+	if got := DBGetWallet("bob"); got != 60 {
+		t.Errorf("bob wallet = %v, want 60", got)
+	}
+	if got := baitAmount(redisClient, "alice", 2); got != 4 {
+		t.Errorf("alice tier 2 bait = %v, want 4", got)
+	}
+	if got := baitAmount(redisClient, "bob", 2); got != 6 {
+		t.Errorf("bob tier 2 bait = %v, want 6", got)
+	}
+	list := DBGetTrades("bob")
+	if len(list.Incoming) != 0 || len(list.History) != 1 {
+		t.Errorf("bob trades = %v incoming %v history, want 0 and 1", len(list.Incoming), len(list.History))
+	}
+}
+
+func TestAcceptTradeFailsWithoutChangesWhenOfferIsGone(t *testing.T) {
+	newTestRedis(t)
+	fish := giveTestFish(t, "alice", InvFish{Name: "Salmon", Price: 40, Tier: 2})
+	DBAddWallet("alice", 100)
+
+	trade, err := DBCreateTrade(TradeOffer{
+		From:  "alice",
+		To:    "bob",
+		Offer: TradeSide{Fish: []string{fish.ID}, Yen: 80},
+	})
+	if err != nil {
+		t.Fatalf("creating trade: %v", err)
+	}
+	// alice spends the yen before bob accepts
+	DBAddWallet("alice", -50)
+
+	if _, err := DBAcceptTrade("bob", trade.ID); err == nil {
+		t.Fatal("accepting a trade alice can no longer pay for succeeded")
+	}
+	if !hasFish("alice", fish.ID) || hasFish("bob", fish.ID) {
+		t.Errorf("fish %s moved even though the trade failed", fish.ID)
+	}
+	if got := DBGetWallet("alice"); got != 50 {
+		t.Errorf("alice wallet = %v, want 50", got)
+	}
+	if got := DBGetWallet("bob"); got != 0 {
+		t.Errorf("bob wallet = %v, want 0", got)
+	}
+	if got, _ := DBGetTrade(trade.ID); got.Status != "pending" {
+		t.Errorf("status = %q, want pending", got.Status)
+	}
+}
+
+func TestAcceptTradeOnlyOnceByRecipient(t *testing.T) {
+	newTestRedis(t)
+	DBAddWallet("alice", 100)
+
+	trade, err := DBCreateTrade(TradeOffer{From: "alice", To: "bob", Offer: TradeSide{Yen: 30}})
+	if err != nil {
+		t.Fatalf("creating trade: %v", err)
+	}
+	if _, err := DBAcceptTrade("alice", trade.ID); err == nil {
+		t.Error("the sender was able to accept their own trade")
+	}
+	if _, err := DBAcceptTrade("bob", trade.ID); err != nil {
+		t.Fatalf("accepting trade: %v", err)
+	}
+	if _, err := DBAcceptTrade("bob", trade.ID); err == nil {
+		t.Error("the trade was accepted twice")
+	}
+	if got := DBGetWallet("bob"); got != 30 {
+		t.Errorf("bob wallet = %v, want 30", got)
+	}
+}
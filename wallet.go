@@ -0,0 +1,29 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/go-redis/redis"
+)
+
+// The wallet is the only yen balance a user has. Selling fish and rewards pay into it
+// and trades, auctions, repairs and gifts are paid out of it, no yen is credited to Tatsumaki.
+
+// DBGetWallet returns the amount of yen in a users wallet
+func DBGetWallet(userID string) int {
+	return walletBalance(redisClient, userID)
+}
+
+// walletBalance reads a users wallet with the given client so it can be used inside transactions
+func walletBalance(c redis.Cmdable, userID string) int {
+	yen, err := strconv.Atoi(c.Get(WalletKey(userID)).Val())
+	if err != nil {
+		return 0
+	}
+	return yen
+}
+
+// DBAddWallet adds yen to a users wallet and returns the new balance
+func DBAddWallet(userID string, amt int) (int64, error) {
+	return redisClient.IncrBy(WalletKey(userID), int64(amt)).Result()
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// scorePeriods are the windows exp boards are kept for besides all-time
+var scorePeriods = []string{"daily", "weekly", "monthly"}
+
+var periodLabels = map[string]string{
+	"daily":   "Daily Points",
+	"weekly":  "Weekly Points",
+	"monthly": "Monthly Points",
+}
+
+// windowStart returns when the window of a period a time falls in started
+func windowStart(period string, t time.Time) time.Time {
+	switch period {
+	case "weekly":
+		return questPeriodEnd("weekly", t).AddDate(0, 0, -7)
+	case "monthly":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// windowEnd returns when the window of a period a time falls in rolls over
+func windowEnd(period string, t time.Time) time.Time {
+	if period == "monthly" {
+		return windowStart(period, t).AddDate(0, 1, 0)
+	}
+	return questPeriodEnd(period, t)
+}
+
+// scoreWindow returns the name of the window of a period a time falls in
+func scoreWindow(period string, t time.Time) string {
+	if period == "monthly" {
+		return "monthly:" + t.Format("2006-01")
+	}
+	return questPeriodKey(period, t)
+}
+
+// windowKey returns the key of an exp board window, an empty guild is the global board
+func windowKey(guildID, window string) string {
+	if guildID == "" {
+		return ScoreWindowKey(window)
+	}
+	return GuildWindowKey(guildID, window)
+}
+
+// queueScoreWindows adds exp to every current window of a global or guild exp board,
+// each window is kept until the one after it rolls over so the previous period stays available
+func queueScoreWindows(pipe redis.Pipeliner, guildID, userID string, amt float64) {
+	for _, p := range scorePeriods {
+		key := windowKey(guildID, scoreWindow(p, CurrentTime))
+		pipe.ZIncrBy(key, amt, userID)
+		pipe.ExpireAt(key, windowEnd(p, windowEnd(p, CurrentTime)))
+	}
+}
+
+// DBScoreWindowBoard returns the key and label of the current or previous window of a global or guild exp board
+func DBScoreWindowBoard(global bool, guildID, period string, previous bool) (string, string, error) {
+	label, ok := periodLabels[period]
+	if !ok {
+		return "", "", fmt.Errorf("Invalid leaderboard period %s", period)
+	}
+	if !global && guildID == "" {
+		return "", "", errors.New("Guild leaderboards need a guild")
+	}
+	if global {
+		guildID = ""
+	}
+	t := CurrentTime
+	if previous {
+		t = windowStart(period, t).Add(-time.Second)
+		label = "Previous " + label
+	}
+	return windowKey(guildID, scoreWindow(period, t)), label, nil
+}